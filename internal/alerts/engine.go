@@ -0,0 +1,272 @@
+package alerts
+
+import (
+	"fmt"
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// stateKey identifies the state of a rule for a server
+type stateKey struct {
+	ruleID   uint
+	serverID uint
+}
+
+// Engine evaluates alert rules against the latest server statuses and
+// publishes alert_firing / alert_resolved events on state changes
+type Engine struct {
+	dbService *database.DatabaseService
+	eventBus  *events.Bus
+	states    map[stateKey]*models.AlertState
+	loaded    bool
+	mutex     sync.Mutex
+}
+
+// NewEngine creates a new alert rules engine
+func NewEngine(dbService *database.DatabaseService, eventBus *events.Bus) *Engine {
+	return &Engine{
+		dbService: dbService,
+		eventBus:  eventBus,
+		states:    make(map[stateKey]*models.AlertState),
+	}
+}
+
+// Evaluate evaluates all enabled rules; it is meant to run after every probe
+// cycle to drop the states of rules that no longer apply
+func (e *Engine) Evaluate(servers []models.Server, statuses map[uint]*models.ServerStatus) {
+	e.EvaluateAt(servers, statuses, time.Now())
+}
+
+// EvaluateServer evaluates the enabled rules selecting a server against a
+// new probe result; it is meant to run after every probe, so that
+// consecutive probes are counted however often a server is probed
+func (e *Engine) EvaluateServer(server *models.Server, status *models.ServerStatus) {
+	e.EvaluateServerAt(server, status, time.Now())
+}
+
+// EvaluateServerAt evaluates the enabled rules selecting a server against
+// its status, using now for the rules' active windows
+func (e *Engine) EvaluateServerAt(server *models.Server, status *models.ServerStatus, now time.Time) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	rules, ok := e.loadRules()
+	if !ok {
+		return
+	}
+
+	for i := range rules {
+		if Selects(&rules[i], server) {
+			e.evaluate(&rules[i], server, status, now)
+		}
+	}
+}
+
+// EvaluateAt evaluates all enabled rules against the given statuses, using
+// now for the rules' active windows
+func (e *Engine) EvaluateAt(servers []models.Server, statuses map[uint]*models.ServerStatus, now time.Time) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	rules, ok := e.loadRules()
+	if !ok {
+		return
+	}
+
+	selected := make(map[stateKey]bool)
+	for i := range rules {
+		rule := &rules[i]
+		for j := range servers {
+			server := &servers[j]
+			if !Selects(rule, server) {
+				continue
+			}
+
+			key := stateKey{ruleID: rule.ID, serverID: server.ID}
+			selected[key] = true
+
+			if status, exists := statuses[server.ID]; exists && status != nil {
+				e.evaluate(rule, server, status, now)
+			}
+		}
+	}
+
+	// Rules that were disabled, deleted or no longer select a server stop alerting
+	for key, state := range e.states {
+		if selected[key] {
+			continue
+		}
+		if state.State == models.AlertStateFiring {
+			e.resolve(state, nil, nil, nil, now)
+		}
+		delete(e.states, key)
+		if err := e.dbService.DeleteAlertState(key.ruleID, key.serverID); err != nil {
+			log.Printf("Failed to delete alert state: %v", err)
+		}
+	}
+}
+
+// evaluate updates the state of one rule for one server with its latest status
+func (e *Engine) evaluate(rule *models.AlertRule, server *models.Server, status *models.ServerStatus, now time.Time) {
+	key := stateKey{ruleID: rule.ID, serverID: server.ID}
+	state, exists := e.states[key]
+	if !exists {
+		state = &models.AlertState{
+			RuleID:   rule.ID,
+			ServerID: server.ID,
+			State:    models.AlertStateInactive,
+		}
+		e.states[key] = state
+	} else if !status.LastUpdated.After(state.LastEvaluated) {
+		return // No new probe result since the last evaluation
+	}
+
+	previousState := state.State
+	state.LastEvaluated = status.LastUpdated
+
+	value, defined := MetricValue(rule.Metric, status)
+	state.Value = value
+	matching := defined && InActiveWindow(rule, now) && Compare(value, rule.Comparator, rule.Threshold)
+
+	switch {
+	case status.Maintenance:
+		// Planned downtime neither fires nor resolves alerts, but restarts pending ones
+		state.ConsecutiveHits = 0
+		state.PendingSince = nil
+		if state.State == models.AlertStatePending {
+			state.State = models.AlertStateInactive
+		}
+	case matching:
+		if state.State != models.AlertStatePending && state.State != models.AlertStateFiring {
+			since := status.LastUpdated
+			state.State = models.AlertStatePending
+			state.PendingSince = &since
+			state.ConsecutiveHits = 0
+		}
+		state.ConsecutiveHits++
+
+		if state.State == models.AlertStatePending && conditionHeld(rule, state, status.LastUpdated) {
+			firedAt := status.LastUpdated
+			state.State = models.AlertStateFiring
+			state.FiredAt = &firedAt
+			state.ResolvedAt = nil
+			e.publish(events.TypeAlertFiring, rule, server, status, value, firedAt,
+				fmt.Sprintf("Alert %s firing for %s: %s (current %s)",
+					rule.Name, server.Name, Condition(rule), formatValue(value)))
+		}
+	default:
+		state.ConsecutiveHits = 0
+		state.PendingSince = nil
+		switch state.State {
+		case models.AlertStateFiring:
+			e.resolve(state, rule, server, status, now)
+		case models.AlertStatePending:
+			state.State = models.AlertStateInactive
+		}
+	}
+
+	// Only persist what is worth showing: state changes and pending progress
+	if state.State != previousState || state.State == models.AlertStatePending {
+		if err := e.dbService.SaveAlertState(state); err != nil {
+			log.Printf("Failed to save alert state for rule %s: %v", rule.Name, err)
+		}
+	}
+}
+
+// resolve moves a firing state to resolved and publishes alert_resolved.
+// rule, server and status are nil when the rule no longer applies.
+func (e *Engine) resolve(state *models.AlertState, rule *models.AlertRule, server *models.Server, status *models.ServerStatus, now time.Time) {
+	resolvedAt := now
+	if status != nil {
+		resolvedAt = status.LastUpdated
+	}
+	state.State = models.AlertStateResolved
+	state.ResolvedAt = &resolvedAt
+
+	if rule == nil || server == nil {
+		// Look up what the event needs; skip the event if either is gone
+		var err error
+		if rule, err = e.dbService.GetAlertRule(state.RuleID); err != nil {
+			return
+		}
+		if server, err = e.dbService.GetServer(state.ServerID); err != nil {
+			return
+		}
+	}
+
+	e.publish(events.TypeAlertResolved, rule, server, status, state.Value, resolvedAt,
+		fmt.Sprintf("Alert %s resolved for %s", rule.Name, server.Name))
+}
+
+// publish logs and publishes an alert event
+func (e *Engine) publish(t events.Type, rule *models.AlertRule, server *models.Server, status *models.ServerStatus, value float64, timestamp time.Time, message string) {
+	log.Printf("Server %s: %s", server.Name, message)
+
+	ruleCopy := *rule
+	e.eventBus.Publish(events.Event{
+		Type:      t,
+		Server:    *server,
+		Current:   status,
+		Timestamp: timestamp,
+		Message:   message,
+		Threshold: rule.Threshold,
+		Rule:      &ruleCopy,
+		Value:     value,
+	})
+}
+
+// loadRules returns the enabled rules, loading the persisted states first
+// if needed; the caller holds the mutex
+func (e *Engine) loadRules() ([]models.AlertRule, bool) {
+	if !e.loaded {
+		if err := e.loadStates(); err != nil {
+			log.Printf("Failed to load alert states: %v", err)
+			return nil, false
+		}
+		e.loaded = true
+	}
+
+	rules, err := e.dbService.GetEnabledAlertRules()
+	if err != nil {
+		log.Printf("Failed to load alert rules: %v", err)
+		return nil, false
+	}
+	return rules, true
+}
+
+// loadStates loads the persisted alert states so firing alerts survive restarts
+func (e *Engine) loadStates() error {
+	states, err := e.dbService.GetAlertStates("")
+	if err != nil {
+		return err
+	}
+	for i := range states {
+		state := states[i]
+		e.states[stateKey{ruleID: state.RuleID, serverID: state.ServerID}] = &state
+	}
+	return nil
+}
+
+// conditionHeld reports whether a pending condition has held long enough:
+// for the rule's duration and for its number of consecutive probes
+func conditionHeld(rule *models.AlertRule, state *models.AlertState, at time.Time) bool {
+	if rule.ConsecutiveProbes > 0 && state.ConsecutiveHits < rule.ConsecutiveProbes {
+		return false
+	}
+	if rule.ForSeconds > 0 {
+		if state.PendingSince == nil || at.Sub(*state.PendingSince) < time.Duration(rule.ForSeconds)*time.Second {
+			return false
+		}
+	}
+	return true
+}
+
+// formatValue formats a metric value without trailing zeros
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,294 @@
+package alerts
+
+import (
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		value      float64
+		comparator string
+		threshold  float64
+		want       bool
+	}{
+		{151, ">", 150, true},
+		{150, ">", 150, false},
+		{95, ">=", 95, true},
+		{0, "==", 0, true},
+		{1, "!=", 0, true},
+		{10, "<", 5, false},
+		{5, "<=", 5, true},
+		{5, "~", 5, false},
+	}
+
+	for _, tt := range tests {
+		if got := Compare(tt.value, tt.comparator, tt.threshold); got != tt.want {
+			t.Errorf("%v %s %v: expected %v, got %v", tt.value, tt.comparator, tt.threshold, tt.want, got)
+		}
+	}
+}
+
+func TestMetricValue(t *testing.T) {
+	online := &models.ServerStatus{Online: true, Players: 19, MaxPlayers: 20, Ping: 80}
+	offline := &models.ServerStatus{Online: false}
+
+	if v, ok := MetricValue(models.MetricPlayerPercent, online); !ok || v != 95 {
+		t.Errorf("Expected player_percent 95, got %v (%v)", v, ok)
+	}
+	if v, ok := MetricValue(models.MetricOnline, offline); !ok || v != 0 {
+		t.Errorf("Expected online 0, got %v (%v)", v, ok)
+	}
+	if _, ok := MetricValue(models.MetricPing, offline); ok {
+		t.Error("Expected ping to be undefined while offline")
+	}
+}
+
+func TestInActiveWindow(t *testing.T) {
+	evenings := &models.AlertRule{ActiveFrom: "18:00", ActiveTo: "02:00"}
+	daytime := &models.AlertRule{ActiveFrom: "09:00", ActiveTo: "17:30"}
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 5, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	if !InActiveWindow(evenings, at(20, 0)) || !InActiveWindow(evenings, at(1, 59)) {
+		t.Error("Expected wrapping window to include 20:00 and 01:59")
+	}
+	if InActiveWindow(evenings, at(2, 0)) || InActiveWindow(evenings, at(12, 0)) {
+		t.Error("Expected wrapping window to exclude 02:00 and 12:00")
+	}
+	if !InActiveWindow(daytime, at(9, 0)) || InActiveWindow(daytime, at(17, 30)) {
+		t.Error("Expected window to include its start and exclude its end")
+	}
+	if !InActiveWindow(&models.AlertRule{}, at(3, 0)) {
+		t.Error("Expected rule without window to always be active")
+	}
+}
+
+func TestSelects(t *testing.T) {
+	server := &models.Server{ID: 3, Type: "cs2", Group: "competitive"}
+
+	if !Selects(&models.AlertRule{}, server) {
+		t.Error("Expected rule without selectors to select every server")
+	}
+	if !Selects(&models.AlertRule{ServerTypes: []string{"cs2"}}, server) {
+		t.Error("Expected type selector to match")
+	}
+	if !Selects(&models.AlertRule{ServerGroups: []string{"competitive"}}, server) {
+		t.Error("Expected group selector to match")
+	}
+	if Selects(&models.AlertRule{ServerIDs: []uint{1, 2}, ServerTypes: []string{"minecraft"}}, server) {
+		t.Error("Expected non-matching selectors not to match")
+	}
+}
+
+// eventRecorder collects published events
+type eventRecorder struct {
+	mutex  sync.Mutex
+	events []events.Event
+}
+
+func (r *eventRecorder) handle(event events.Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) wait(t *testing.T, count int) []events.Event {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		r.mutex.Lock()
+		if len(r.events) >= count {
+			result := append([]events.Event(nil), r.events...)
+			r.mutex.Unlock()
+			return result
+		}
+		r.mutex.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Expected %d events", count)
+	return nil
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+	dbService := database.NewDatabaseService()
+
+	server, err := dbService.CreateServer(&models.CreateServerRequest{
+		Name:    "Alert Test",
+		Type:    "minecraft",
+		Address: "localhost",
+		Port:    25565,
+	})
+	if err != nil {
+		t.Fatal("Failed to create test server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	pingRule, err := dbService.CreateAlertRule(&models.CreateAlertRuleRequest{
+		Name:       "High ping",
+		ServerIDs:  []uint{server.ID},
+		Metric:     models.MetricPing,
+		Comparator: ">",
+		Threshold:  150,
+		ForSeconds: 300,
+	})
+	if err != nil {
+		t.Fatal("Failed to create ping rule:", err)
+	}
+	defer dbService.DeleteAlertRule(pingRule.ID)
+
+	offlineRule, err := dbService.CreateAlertRule(&models.CreateAlertRuleRequest{
+		Name:              "Offline",
+		ServerIDs:         []uint{server.ID},
+		Metric:            models.MetricOnline,
+		Comparator:        "==",
+		Threshold:         0,
+		ConsecutiveProbes: 3,
+	})
+	if err != nil {
+		t.Fatal("Failed to create offline rule:", err)
+	}
+	defer dbService.DeleteAlertRule(offlineRule.ID)
+
+	bus := events.NewBus()
+	defer bus.Close()
+	recorder := &eventRecorder{}
+	bus.Subscribe("test", recorder.handle)
+
+	engine := NewEngine(dbService, bus)
+	servers := []models.Server{*server}
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	probe := func(minute int, status models.ServerStatus) {
+		status.LastUpdated = start.Add(time.Duration(minute) * time.Minute)
+		engine.EvaluateAt(servers, map[uint]*models.ServerStatus{server.ID: &status}, status.LastUpdated)
+	}
+
+	// High ping for 6 minutes fires once after 5 minutes
+	for minute := 0; minute <= 6; minute++ {
+		probe(minute, models.ServerStatus{Online: true, Ping: 200})
+	}
+	firing := recorder.wait(t, 1)
+	if firing[0].Type != events.TypeAlertFiring || firing[0].Rule.ID != pingRule.ID || firing[0].Value != 200 {
+		t.Fatalf("Unexpected event: %+v", firing[0])
+	}
+	if !firing[0].Timestamp.Equal(start.Add(5 * time.Minute)) {
+		t.Errorf("Expected alert to fire after 5 minutes, fired at %v", firing[0].Timestamp)
+	}
+
+	states, err := dbService.GetAlertStates(models.AlertStateFiring)
+	if err != nil || len(states) != 1 || states[0].RuleID != pingRule.ID {
+		t.Fatalf("Expected firing state in the database, got %+v (%v)", states, err)
+	}
+
+	// Two offline probes resolve the ping alert but do not fire the offline alert yet
+	probe(7, models.ServerStatus{Online: false})
+	probe(8, models.ServerStatus{Online: false})
+	resolved := recorder.wait(t, 2)
+	if resolved[1].Type != events.TypeAlertResolved || resolved[1].Rule.ID != pingRule.ID {
+		t.Fatalf("Unexpected event: %+v", resolved[1])
+	}
+
+	// The same probe result evaluated again does not count as another probe
+	probe(8, models.ServerStatus{Online: false})
+	probe(9, models.ServerStatus{Online: false})
+	offline := recorder.wait(t, 3)
+	if offline[2].Type != events.TypeAlertFiring || offline[2].Rule.ID != offlineRule.ID {
+		t.Fatalf("Unexpected event: %+v", offline[2])
+	}
+	if !offline[2].Timestamp.Equal(start.Add(9 * time.Minute)) {
+		t.Errorf("Expected offline alert on the third offline probe, fired at %v", offline[2].Timestamp)
+	}
+
+	// Disabling the rule resolves its firing alert
+	disabled := false
+	_, err = dbService.UpdateAlertRule(offlineRule.ID, &models.UpdateAlertRuleRequest{
+		Name:              offlineRule.Name,
+		Enabled:           &disabled,
+		ServerIDs:         offlineRule.ServerIDs,
+		Metric:            offlineRule.Metric,
+		Comparator:        offlineRule.Comparator,
+		ConsecutiveProbes: offlineRule.ConsecutiveProbes,
+	})
+	if err != nil {
+		t.Fatal("Failed to disable rule:", err)
+	}
+	probe(10, models.ServerStatus{Online: false})
+	disabledEvents := recorder.wait(t, 4)
+	if disabledEvents[3].Type != events.TypeAlertResolved || disabledEvents[3].Rule.ID != offlineRule.ID {
+		t.Fatalf("Unexpected event: %+v", disabledEvents[3])
+	}
+}
+
+func TestEngine_EvaluateServer(t *testing.T) {
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+	dbService := database.NewDatabaseService()
+
+	server, err := dbService.CreateServer(&models.CreateServerRequest{
+		Name:    "Per Probe",
+		Type:    "minecraft",
+		Address: "localhost",
+		Port:    25565,
+	})
+	if err != nil {
+		t.Fatal("Failed to create test server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	rule, err := dbService.CreateAlertRule(&models.CreateAlertRuleRequest{
+		Name:              "Offline",
+		ServerIDs:         []uint{server.ID},
+		Metric:            models.MetricOnline,
+		Comparator:        "==",
+		Threshold:         0,
+		ConsecutiveProbes: 3,
+	})
+	if err != nil {
+		t.Fatal("Failed to create rule:", err)
+	}
+	defer dbService.DeleteAlertRule(rule.ID)
+
+	bus := events.NewBus()
+	defer bus.Close()
+	recorder := &eventRecorder{}
+	bus.Subscribe("test", recorder.handle)
+
+	engine := NewEngine(dbService, bus)
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// Three probes within a few seconds count as three, without a cycle in between
+	for probe := 0; probe < 3; probe++ {
+		status := &models.ServerStatus{Online: false, LastUpdated: start.Add(time.Duration(probe) * 5 * time.Second)}
+		engine.EvaluateServerAt(server, status, status.LastUpdated)
+	}
+	firing := recorder.wait(t, 1)
+	if firing[0].Type != events.TypeAlertFiring || firing[0].Rule.ID != rule.ID {
+		t.Fatalf("Unexpected event: %+v", firing[0])
+	}
+	if !firing[0].Timestamp.Equal(start.Add(10 * time.Second)) {
+		t.Errorf("Expected the alert to fire on the third probe, fired at %v", firing[0].Timestamp)
+	}
+
+	// The cycle evaluation of the same result does not count it again
+	status := &models.ServerStatus{Online: false, LastUpdated: start.Add(10 * time.Second)}
+	engine.EvaluateAt([]models.Server{*server}, map[uint]*models.ServerStatus{server.ID: status}, status.LastUpdated)
+	states, err := dbService.GetAlertStates(models.AlertStateFiring)
+	if err != nil {
+		t.Fatal("Failed to get alert states:", err)
+	}
+	for _, state := range states {
+		if state.RuleID == rule.ID && state.ConsecutiveHits != 3 {
+			t.Errorf("Expected 3 consecutive hits, got %d", state.ConsecutiveHits)
+		}
+	}
+}
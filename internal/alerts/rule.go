@@ -0,0 +1,115 @@
+package alerts
+
+import (
+	"game-server-monitor/internal/models"
+	"time"
+)
+
+// Selects reports whether a rule applies to a server. A rule without any
+// selector applies to every server; otherwise the server must be listed by
+// ID, belong to a listed group, or have a listed type.
+func Selects(rule *models.AlertRule, server *models.Server) bool {
+	if len(rule.ServerIDs) == 0 && len(rule.ServerGroups) == 0 && len(rule.ServerTypes) == 0 {
+		return true
+	}
+
+	for _, id := range rule.ServerIDs {
+		if id == server.ID {
+			return true
+		}
+	}
+	for _, group := range rule.ServerGroups {
+		if server.Group != "" && group == server.Group {
+			return true
+		}
+	}
+	for _, t := range rule.ServerTypes {
+		if t == server.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// MetricValue reads a rule metric from a status. Metrics other than "online"
+// are only defined while the server is online.
+func MetricValue(metric string, status *models.ServerStatus) (float64, bool) {
+	if metric == models.MetricOnline {
+		if status.Online {
+			return 1, true
+		}
+		return 0, true
+	}
+
+	if !status.Online {
+		return 0, false
+	}
+
+	switch metric {
+	case models.MetricPlayers:
+		return float64(status.Players), true
+	case models.MetricMaxPlayers:
+		return float64(status.MaxPlayers), true
+	case models.MetricPlayerPercent:
+		if status.MaxPlayers <= 0 {
+			return 0, false
+		}
+		return float64(status.Players) * 100 / float64(status.MaxPlayers), true
+	case models.MetricPing:
+		return float64(status.Ping), true
+	default:
+		return 0, false
+	}
+}
+
+// Compare applies a rule comparator to a value and threshold
+func Compare(value float64, comparator string, threshold float64) bool {
+	switch comparator {
+	case models.ComparatorGreater:
+		return value > threshold
+	case models.ComparatorGreaterEqual:
+		return value >= threshold
+	case models.ComparatorLess:
+		return value < threshold
+	case models.ComparatorLessEqual:
+		return value <= threshold
+	case models.ComparatorEqual:
+		return value == threshold
+	case models.ComparatorNotEqual:
+		return value != threshold
+	default:
+		return false
+	}
+}
+
+// InActiveWindow reports whether t falls into the rule's daily active window.
+// Rules without a window are always active. A window whose end is before its
+// start wraps past midnight, e.g. 18:00-02:00.
+func InActiveWindow(rule *models.AlertRule, t time.Time) bool {
+	if rule.ActiveFrom == "" || rule.ActiveTo == "" {
+		return true
+	}
+
+	from, err := time.Parse("15:04", rule.ActiveFrom)
+	if err != nil {
+		return true
+	}
+	to, err := time.Parse("15:04", rule.ActiveTo)
+	if err != nil {
+		return true
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	start := from.Hour()*60 + from.Minute()
+	end := to.Hour()*60 + to.Minute()
+
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// Condition describes a rule as text, e.g. "ping > 150"
+func Condition(rule *models.AlertRule) string {
+	return rule.Metric + " " + rule.Comparator + " " + formatValue(rule.Threshold)
+}
@@ -9,8 +9,10 @@ import (
 // CacheManager interface defines the contract for cache operations
 type CacheManager interface {
 	SetServerStatus(serverID uint, status *models.ServerStatus)
+	SetServerStatusWithTTL(serverID uint, status *models.ServerStatus, ttl time.Duration)
 	GetServerStatus(serverID uint) (*models.ServerStatus, bool)
 	GetAllServerStatuses() map[uint]*models.ServerStatus
+	Delete(serverID uint)
 	ClearExpiredEntries()
 	Clear()
 	Size() int
@@ -45,12 +47,17 @@ func NewMemoryCache(ttl time.Duration) CacheManager {
 
 // SetServerStatus stores server status in cache with TTL
 func (mc *MemoryCache) SetServerStatus(serverID uint, status *models.ServerStatus) {
+	mc.SetServerStatusWithTTL(serverID, status, mc.ttl)
+}
+
+// SetServerStatusWithTTL stores server status in cache with a TTL of its own
+func (mc *MemoryCache) SetServerStatusWithTTL(serverID uint, status *models.ServerStatus, ttl time.Duration) {
 	mc.mutex.Lock()
 	defer mc.mutex.Unlock()
 
 	mc.data[serverID] = &CachedStatus{
 		Status:    status,
-		ExpiresAt: time.Now().Add(mc.ttl),
+		ExpiresAt: time.Now().Add(ttl),
 	}
 }
 
@@ -95,6 +102,14 @@ func (mc *MemoryCache) GetAllServerStatuses() map[uint]*models.ServerStatus {
 	return result
 }
 
+// Delete removes a server's status from cache
+func (mc *MemoryCache) Delete(serverID uint) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	delete(mc.data, serverID)
+}
+
 // ClearExpiredEntries removes all expired entries from cache
 func (mc *MemoryCache) ClearExpiredEntries() {
 	mc.mutex.Lock()
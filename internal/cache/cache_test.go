@@ -67,6 +67,22 @@ func TestMemoryCache_Expiration(t *testing.T) {
 	}
 }
 
+func TestMemoryCache_SetWithTTL(t *testing.T) {
+	cache := NewMemoryCache(50 * time.Millisecond)
+
+	cache.SetServerStatus(1, &models.ServerStatus{Online: true})
+	cache.SetServerStatusWithTTL(2, &models.ServerStatus{Online: true}, time.Minute)
+
+	time.Sleep(100 * time.Millisecond)
+
+	if _, found := cache.GetServerStatus(1); found {
+		t.Error("Expected the default TTL to expire")
+	}
+	if _, found := cache.GetServerStatus(2); !found {
+		t.Error("Expected the longer TTL to keep the status")
+	}
+}
+
 func TestMemoryCache_GetAllServerStatuses(t *testing.T) {
 	cache := NewMemoryCache(time.Minute)
 
@@ -91,6 +107,23 @@ func TestMemoryCache_GetAllServerStatuses(t *testing.T) {
 	}
 }
 
+func TestMemoryCache_Delete(t *testing.T) {
+	cache := NewMemoryCache(time.Minute)
+
+	cache.SetServerStatus(1, &models.ServerStatus{Online: true})
+	cache.SetServerStatus(2, &models.ServerStatus{Online: true})
+
+	cache.Delete(1)
+	cache.Delete(3) // Unknown servers are ignored
+
+	if _, found := cache.GetServerStatus(1); found {
+		t.Error("Expected deleted status to be gone")
+	}
+	if _, found := cache.GetServerStatus(2); !found {
+		t.Error("Expected other statuses to be kept")
+	}
+}
+
 func TestMemoryCache_ClearExpiredEntries(t *testing.T) {
 	cache := NewMemoryCache(100 * time.Millisecond)
 
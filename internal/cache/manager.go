@@ -32,6 +32,14 @@ func (scm *StatusCacheManager) UpdateServerStatus(serverID uint, status *models.
 		serverID, status.Online, status.Players, status.MaxPlayers)
 }
 
+// UpdateServerStatusWithTTL updates the cached status for a server, keeping
+// it for ttl instead of the default TTL
+func (scm *StatusCacheManager) UpdateServerStatusWithTTL(serverID uint, status *models.ServerStatus, ttl time.Duration) {
+	scm.cache.SetServerStatusWithTTL(serverID, status, ttl)
+	log.Printf("Updated cache for server ID %d: online=%t, players=%d/%d",
+		serverID, status.Online, status.Players, status.MaxPlayers)
+}
+
 // GetServerStatus retrieves cached status for a server
 func (scm *StatusCacheManager) GetServerStatus(serverID uint) (*models.ServerStatus, bool) {
 	return scm.cache.GetServerStatus(serverID)
@@ -59,6 +67,11 @@ func (scm *StatusCacheManager) GetServerStatusWithFallback(serverID uint) *model
 	}
 }
 
+// RemoveServerStatus drops the cached status of a server
+func (scm *StatusCacheManager) RemoveServerStatus(serverID uint) {
+	scm.cache.Delete(serverID)
+}
+
 // ClearExpiredEntries removes expired entries from cache
 func (scm *StatusCacheManager) ClearExpiredEntries() {
 	scm.cache.ClearExpiredEntries()
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"errors"
+	"game-server-monitor/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AlertOperations provides CRUD operations for alert rules and their states
+type AlertOperations struct {
+	db *gorm.DB
+}
+
+// NewAlertOperations creates a new AlertOperations instance
+func NewAlertOperations() *AlertOperations {
+	return &AlertOperations{db: DB}
+}
+
+// CreateRule creates a new alert rule in the database
+func (a *AlertOperations) CreateRule(rule *models.AlertRule) error {
+	return a.db.Create(rule).Error
+}
+
+// GetRuleByID retrieves an alert rule by its ID
+func (a *AlertOperations) GetRuleByID(id uint) (*models.AlertRule, error) {
+	var rule models.AlertRule
+	if err := a.db.First(&rule, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("alert rule not found")
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// GetAllRules retrieves all alert rules
+func (a *AlertOperations) GetAllRules() ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	if err := a.db.Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetEnabledRules retrieves all enabled alert rules
+func (a *AlertOperations) GetEnabledRules() ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	if err := a.db.Where("enabled = ?", true).Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SaveRule stores all fields of an existing alert rule
+func (a *AlertOperations) SaveRule(rule *models.AlertRule) error {
+	return a.db.Save(rule).Error
+}
+
+// DeleteRule deletes an alert rule and its states
+func (a *AlertOperations) DeleteRule(id uint) error {
+	result := a.db.Delete(&models.AlertRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("alert rule not found")
+	}
+	return a.DeleteStatesForRule(id)
+}
+
+// GetStates retrieves alert states, optionally only those in the given state
+func (a *AlertOperations) GetStates(state string) ([]models.AlertState, error) {
+	var states []models.AlertState
+	query := a.db.Order("rule_id, server_id")
+	if state != "" {
+		query = query.Where("state = ?", state)
+	}
+	if err := query.Find(&states).Error; err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// SaveState inserts or updates the state of a rule for a server
+func (a *AlertOperations) SaveState(state *models.AlertState) error {
+	return a.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "rule_id"}, {Name: "server_id"}},
+		UpdateAll: true,
+	}).Create(state).Error
+}
+
+// DeleteState deletes the state of a rule for a server
+func (a *AlertOperations) DeleteState(ruleID, serverID uint) error {
+	return a.db.Where("rule_id = ? AND server_id = ?", ruleID, serverID).Delete(&models.AlertState{}).Error
+}
+
+// DeleteStatesForRule deletes all states of a rule
+func (a *AlertOperations) DeleteStatesForRule(ruleID uint) error {
+	return a.db.Where("rule_id = ?", ruleID).Delete(&models.AlertState{}).Error
+}
+
+// DeleteStatesForServer deletes all alert states of a server
+func (a *AlertOperations) DeleteStatesForServer(serverID uint) error {
+	return a.db.Where("server_id = ?", serverID).Delete(&models.AlertState{}).Error
+}
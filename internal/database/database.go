@@ -2,6 +2,7 @@ package database
 
 import (
 	"game-server-monitor/internal/models"
+	"game-server-monitor/internal/secrets"
 	"log"
 
 	"gorm.io/driver/sqlite"
@@ -19,11 +20,28 @@ func Initialize() error {
 	}
 
 	// Auto-migrate the schema
-	err = DB.AutoMigrate(&models.Server{}, &models.User{})
+	err = DB.AutoMigrate(
+		&models.Server{},
+		&models.User{},
+		&models.StatusSample{},
+		&models.StatusRollup{},
+		&models.NotificationChannel{},
+		&models.NotificationDelivery{},
+		&models.AlertRule{},
+		&models.AlertState{},
+		&models.MaintenanceWindow{},
+		&models.ServerIcon{},
+		&models.RCONAuditEntry{},
+	)
 	if err != nil {
 		return err
 	}
 
+	// Channels created before credentials were encrypted
+	if err := encryptChannelCredentials(); err != nil {
+		log.Printf("Warning: Failed to encrypt notification channel credentials: %v", err)
+	}
+
 	// Create default admin user if no users exist
 	if err := createDefaultAdmin(); err != nil {
 		log.Printf("Warning: Failed to create default admin user: %v", err)
@@ -32,6 +50,35 @@ func Initialize() error {
 	return nil
 }
 
+// encryptChannelCredentials encrypts webhook secrets and SMTP passwords that
+// are still stored as plaintext
+func encryptChannelCredentials() error {
+	var channels []models.NotificationChannel
+	if err := DB.Find(&channels).Error; err != nil {
+		return err
+	}
+
+	for i := range channels {
+		channel := &channels[i]
+		if isStoredEncrypted(channel.Secret) && isStoredEncrypted(channel.SMTPPassword) {
+			continue
+		}
+
+		if err := encryptCredentials(channel); err != nil {
+			return err
+		}
+		if err := DB.Save(channel).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isStoredEncrypted reports whether a credential needs no encryption
+func isStoredEncrypted(value string) bool {
+	return value == "" || secrets.IsEncrypted(value)
+}
+
 // createDefaultAdmin creates a default admin user if no users exist
 func createDefaultAdmin() error {
 	var count int64
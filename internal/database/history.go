@@ -0,0 +1,113 @@
+package database
+
+import (
+	"game-server-monitor/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HistoryOperations provides storage operations for probe history.
+// Timestamps are stored and compared in UTC.
+type HistoryOperations struct {
+	db *gorm.DB
+}
+
+// NewHistoryOperations creates a new HistoryOperations instance
+func NewHistoryOperations() *HistoryOperations {
+	return &HistoryOperations{db: DB}
+}
+
+// CreateSample stores a single probe result
+func (h *HistoryOperations) CreateSample(sample *models.StatusSample) error {
+	return h.db.Create(sample).Error
+}
+
+// GetSamples retrieves the probe results of a server in [from, to), oldest first
+func (h *HistoryOperations) GetSamples(serverID uint, from, to time.Time) ([]models.StatusSample, error) {
+	var samples []models.StatusSample
+	err := h.db.
+		Where("server_id = ? AND timestamp >= ? AND timestamp < ?", serverID, from.UTC(), to.UTC()).
+		Order("timestamp ASC").
+		Find(&samples).Error
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// EarliestSampleTime returns the timestamp of the oldest stored probe result of a server
+func (h *HistoryOperations) EarliestSampleTime(serverID uint) (time.Time, bool, error) {
+	var sample models.StatusSample
+	result := h.db.Where("server_id = ?", serverID).Order("timestamp ASC").Limit(1).Find(&sample)
+	if result.Error != nil {
+		return time.Time{}, false, result.Error
+	}
+	return sample.Timestamp, result.RowsAffected > 0, nil
+}
+
+// DeleteSamplesBefore removes all probe results older than the given time
+func (h *HistoryOperations) DeleteSamplesBefore(before time.Time) (int64, error) {
+	result := h.db.Where("timestamp < ?", before.UTC()).Delete(&models.StatusSample{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteSamplesForServer removes all probe results and rollups of a server
+func (h *HistoryOperations) DeleteSamplesForServer(serverID uint) error {
+	if err := h.db.Where("server_id = ?", serverID).Delete(&models.StatusSample{}).Error; err != nil {
+		return err
+	}
+	return h.db.Where("server_id = ?", serverID).Delete(&models.StatusRollup{}).Error
+}
+
+// GetRollups retrieves the rollups of a server and tier with bucket starts in [from, to), oldest first
+func (h *HistoryOperations) GetRollups(serverID uint, tier string, from, to time.Time) ([]models.StatusRollup, error) {
+	var rollups []models.StatusRollup
+	err := h.db.
+		Where("server_id = ? AND tier = ? AND bucket_start >= ? AND bucket_start < ?", serverID, tier, from.UTC(), to.UTC()).
+		Order("bucket_start ASC").
+		Find(&rollups).Error
+	if err != nil {
+		return nil, err
+	}
+	return rollups, nil
+}
+
+// LatestRollupStart returns the bucket start of the newest rollup of a server and tier
+func (h *HistoryOperations) LatestRollupStart(serverID uint, tier string) (time.Time, bool, error) {
+	var rollup models.StatusRollup
+	result := h.db.Where("server_id = ? AND tier = ?", serverID, tier).Order("bucket_start DESC").Limit(1).Find(&rollup)
+	if result.Error != nil {
+		return time.Time{}, false, result.Error
+	}
+	return rollup.BucketStart, result.RowsAffected > 0, nil
+}
+
+// EarliestRollupStart returns the bucket start of the oldest rollup of a server and tier
+func (h *HistoryOperations) EarliestRollupStart(serverID uint, tier string) (time.Time, bool, error) {
+	var rollup models.StatusRollup
+	result := h.db.Where("server_id = ? AND tier = ?", serverID, tier).Order("bucket_start ASC").Limit(1).Find(&rollup)
+	if result.Error != nil {
+		return time.Time{}, false, result.Error
+	}
+	return rollup.BucketStart, result.RowsAffected > 0, nil
+}
+
+// UpsertRollups stores rollups, replacing existing ones for the same bucket
+func (h *HistoryOperations) UpsertRollups(rollups []models.StatusRollup) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	return h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "server_id"}, {Name: "tier"}, {Name: "bucket_start"}},
+		UpdateAll: true,
+	}).Create(&rollups).Error
+}
+
+// DeleteRollupsBefore removes all rollups of a tier with bucket starts older than the given time
+func (h *HistoryOperations) DeleteRollupsBefore(tier string, before time.Time) (int64, error) {
+	result := h.db.Where("tier = ? AND bucket_start < ?", tier, before.UTC()).Delete(&models.StatusRollup{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"errors"
+	"game-server-monitor/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IconOperations provides operations for stored server icons
+type IconOperations struct {
+	db *gorm.DB
+}
+
+// NewIconOperations creates a new IconOperations instance
+func NewIconOperations() *IconOperations {
+	return &IconOperations{db: DB}
+}
+
+// Save stores the icon of a server, replacing the previous one
+func (i *IconOperations) Save(icon *models.ServerIcon) error {
+	return i.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(icon).Error
+}
+
+// Get retrieves the icon of a server
+func (i *IconOperations) Get(serverID uint) (*models.ServerIcon, error) {
+	var icon models.ServerIcon
+	if err := i.db.First(&icon, "server_id = ?", serverID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("server icon not found")
+		}
+		return nil, err
+	}
+	return &icon, nil
+}
+
+// GetHashes retrieves the icon hashes of all servers with an icon, keyed by server ID
+func (i *IconOperations) GetHashes() (map[uint]string, error) {
+	var icons []models.ServerIcon
+	if err := i.db.Select("server_id", "hash").Find(&icons).Error; err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[uint]string, len(icons))
+	for _, icon := range icons {
+		hashes[icon.ServerID] = icon.Hash
+	}
+	return hashes, nil
+}
+
+// Delete removes the icon of a server
+func (i *IconOperations) Delete(serverID uint) error {
+	return i.db.Where("server_id = ?", serverID).Delete(&models.ServerIcon{}).Error
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"errors"
+	"game-server-monitor/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// MaintenanceOperations provides CRUD operations for maintenance windows
+type MaintenanceOperations struct {
+	db *gorm.DB
+}
+
+// NewMaintenanceOperations creates a new MaintenanceOperations instance
+func NewMaintenanceOperations() *MaintenanceOperations {
+	return &MaintenanceOperations{db: DB}
+}
+
+// Create creates a new maintenance window in the database
+func (m *MaintenanceOperations) Create(window *models.MaintenanceWindow) error {
+	return m.db.Create(window).Error
+}
+
+// GetByID retrieves a maintenance window by its ID
+func (m *MaintenanceOperations) GetByID(id uint) (*models.MaintenanceWindow, error) {
+	var window models.MaintenanceWindow
+	if err := m.db.First(&window, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("maintenance window not found")
+		}
+		return nil, err
+	}
+	return &window, nil
+}
+
+// GetAll retrieves all maintenance windows
+func (m *MaintenanceOperations) GetAll() ([]models.MaintenanceWindow, error) {
+	var windows []models.MaintenanceWindow
+	if err := m.db.Find(&windows).Error; err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// GetEnabled retrieves all enabled maintenance windows
+func (m *MaintenanceOperations) GetEnabled() ([]models.MaintenanceWindow, error) {
+	var windows []models.MaintenanceWindow
+	if err := m.db.Where("enabled = ?", true).Find(&windows).Error; err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// Save stores all fields of an existing maintenance window
+func (m *MaintenanceOperations) Save(window *models.MaintenanceWindow) error {
+	return m.db.Save(window).Error
+}
+
+// Delete deletes a maintenance window by its ID
+func (m *MaintenanceOperations) Delete(id uint) error {
+	result := m.db.Delete(&models.MaintenanceWindow{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("maintenance window not found")
+	}
+	return nil
+}
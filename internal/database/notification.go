@@ -0,0 +1,89 @@
+package database
+
+import (
+	"errors"
+	"game-server-monitor/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NotificationOperations provides CRUD operations for notification channels and deliveries
+type NotificationOperations struct {
+	db *gorm.DB
+}
+
+// NewNotificationOperations creates a new NotificationOperations instance
+func NewNotificationOperations() *NotificationOperations {
+	return &NotificationOperations{db: DB}
+}
+
+// CreateChannel creates a new notification channel in the database
+func (n *NotificationOperations) CreateChannel(channel *models.NotificationChannel) error {
+	return n.db.Create(channel).Error
+}
+
+// GetChannelByID retrieves a notification channel by its ID
+func (n *NotificationOperations) GetChannelByID(id uint) (*models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	if err := n.db.First(&channel, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notification channel not found")
+		}
+		return nil, err
+	}
+	return &channel, nil
+}
+
+// GetAllChannels retrieves all notification channels
+func (n *NotificationOperations) GetAllChannels() ([]models.NotificationChannel, error) {
+	var channels []models.NotificationChannel
+	if err := n.db.Find(&channels).Error; err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// GetEnabledChannels retrieves all enabled notification channels
+func (n *NotificationOperations) GetEnabledChannels() ([]models.NotificationChannel, error) {
+	var channels []models.NotificationChannel
+	if err := n.db.Where("enabled = ?", true).Find(&channels).Error; err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// SaveChannel stores all fields of an existing notification channel
+func (n *NotificationOperations) SaveChannel(channel *models.NotificationChannel) error {
+	return n.db.Save(channel).Error
+}
+
+// DeleteChannel deletes a notification channel and its delivery log
+func (n *NotificationOperations) DeleteChannel(id uint) error {
+	result := n.db.Delete(&models.NotificationChannel{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("notification channel not found")
+	}
+	return n.db.Where("channel_id = ?", id).Delete(&models.NotificationDelivery{}).Error
+}
+
+// CreateDelivery records a delivery attempt outcome
+func (n *NotificationOperations) CreateDelivery(delivery *models.NotificationDelivery) error {
+	return n.db.Create(delivery).Error
+}
+
+// GetDeliveries retrieves the most recent deliveries of a channel, newest first
+func (n *NotificationOperations) GetDeliveries(channelID uint, limit int) ([]models.NotificationDelivery, error) {
+	var deliveries []models.NotificationDelivery
+	err := n.db.
+		Where("channel_id = ?", channelID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -24,9 +24,25 @@ func (s *ServerOperations) CreateServer(req *models.CreateServerRequest) (*model
 		Type:        req.Type,
 		Address:     req.Address,
 		Port:        req.Port,
+		QueryPort:   req.QueryPort,
 		Description: req.Description,
 		DownloadURL: req.DownloadURL,
 		Changelog:   req.Changelog,
+		Group:       req.Group,
+		EnableQuery: req.EnableQuery,
+		HidePlayers: req.HidePlayers,
+		DualStack:   req.DualStack,
+
+		RCONPort:     req.RCONPort,
+		RCONPassword: req.RCONPassword,
+		RCONCommands: req.RCONCommands,
+
+		Check: req.Check,
+
+		ProbeIntervalSeconds: req.ProbeIntervalSeconds,
+		ProbeTimeoutSeconds:  req.ProbeTimeoutSeconds,
+		ProbeRetries:         req.ProbeRetries,
+		RetryBackoffSeconds:  req.RetryBackoffSeconds,
 	}
 
 	if err := s.db.Create(server).Error; err != nil {
@@ -72,9 +88,24 @@ func (s *ServerOperations) UpdateServer(id uint, req *models.UpdateServerRequest
 	server.Type = req.Type
 	server.Address = req.Address
 	server.Port = req.Port
+	server.QueryPort = req.QueryPort
 	server.Description = req.Description
 	server.DownloadURL = req.DownloadURL
 	server.Changelog = req.Changelog
+	server.Group = req.Group
+	server.EnableQuery = req.EnableQuery
+	server.HidePlayers = req.HidePlayers
+	server.DualStack = req.DualStack
+	server.RCONPort = req.RCONPort
+	server.RCONCommands = req.RCONCommands
+	server.Check = req.Check
+	server.ProbeIntervalSeconds = req.ProbeIntervalSeconds
+	server.ProbeTimeoutSeconds = req.ProbeTimeoutSeconds
+	server.ProbeRetries = req.ProbeRetries
+	server.RetryBackoffSeconds = req.RetryBackoffSeconds
+	if req.RCONPassword != "" {
+		server.RCONPassword = req.RCONPassword
+	}
 
 	if err := s.db.Save(&server).Error; err != nil {
 		return nil, err
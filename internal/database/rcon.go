@@ -0,0 +1,36 @@
+package database
+
+import (
+	"game-server-monitor/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RCONOperations provides operations for the RCON audit log
+type RCONOperations struct {
+	db *gorm.DB
+}
+
+// NewRCONOperations creates a new RCONOperations instance
+func NewRCONOperations() *RCONOperations {
+	return &RCONOperations{db: DB}
+}
+
+// CreateAuditEntry records an RCON command
+func (r *RCONOperations) CreateAuditEntry(entry *models.RCONAuditEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// GetAuditEntries retrieves the most recent audit entries of a server, newest first
+func (r *RCONOperations) GetAuditEntries(serverID uint, limit int) ([]models.RCONAuditEntry, error) {
+	var entries []models.RCONAuditEntry
+	err := r.db.
+		Where("server_id = ?", serverID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
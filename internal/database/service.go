@@ -1,27 +1,49 @@
 package database
 
 import (
+	"bytes"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"game-server-monitor/internal/cron"
+	"game-server-monitor/internal/events"
 	"game-server-monitor/internal/models"
+	"game-server-monitor/internal/protocol"
+	"game-server-monitor/internal/secrets"
+	"log"
+	"text/template"
+	"time"
 
 	"golang.org/x/crypto/argon2"
 )
 
 // DatabaseService provides high-level database operations
 type DatabaseService struct {
-	ServerOps *ServerOperations
-	UserOps   *UserOperations
+	ServerOps       *ServerOperations
+	UserOps         *UserOperations
+	HistoryOps      *HistoryOperations
+	NotificationOps *NotificationOperations
+	AlertOps        *AlertOperations
+	MaintenanceOps  *MaintenanceOperations
+	IconOps         *IconOperations
+	RCONOps         *RCONOperations
 }
 
 // NewDatabaseService creates a new DatabaseService instance
 func NewDatabaseService() *DatabaseService {
 	return &DatabaseService{
-		ServerOps: NewServerOperations(),
-		UserOps:   NewUserOperations(),
+		ServerOps:       NewServerOperations(),
+		UserOps:         NewUserOperations(),
+		HistoryOps:      NewHistoryOperations(),
+		NotificationOps: NewNotificationOperations(),
+		AlertOps:        NewAlertOperations(),
+		MaintenanceOps:  NewMaintenanceOperations(),
+		IconOps:         NewIconOperations(),
+		RCONOps:         NewRCONOperations(),
 	}
 }
 
@@ -30,16 +52,46 @@ func NewDatabaseService() *DatabaseService {
 // CreateServer creates a new server with validation
 func (ds *DatabaseService) CreateServer(req *models.CreateServerRequest) (*models.Server, error) {
 	// Validate server type
-	if req.Type != "minecraft" && req.Type != "cs2" {
-		return nil, errors.New("invalid server type: must be 'minecraft' or 'cs2'")
+	serverType, ok := protocol.Lookup(req.Type)
+	if !ok {
+		return nil, fmt.Errorf("invalid server type: must be one of %s", protocol.Names())
 	}
 
-	// Validate port range
-	if req.Port < 1 || req.Port > 65535 {
+	// Validate port range; types located via SRV records may omit the port,
+	// other types fall back to their default port
+	if req.Port == 0 && serverType.SRVService == "" {
+		if serverType.DefaultPort == 0 {
+			return nil, fmt.Errorf("port is required for server type %s", req.Type)
+		}
+		req.Port = serverType.DefaultPort
+	}
+	if req.Port < 0 || req.Port > 65535 {
 		return nil, errors.New("invalid port: must be between 1 and 65535")
 	}
+	if req.QueryPort < 0 || req.QueryPort > 65535 {
+		return nil, errors.New("invalid query port: must be between 1 and 65535")
+	}
+	if req.RCONPort < 0 || req.RCONPort > 65535 {
+		return nil, errors.New("invalid RCON port: must be between 1 and 65535")
+	}
+	if serverType.ValidateCheck != nil {
+		if err := serverType.ValidateCheck(&req.Check); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateProbeSettings(req.ProbeIntervalSeconds, req.ProbeTimeoutSeconds, req.ProbeRetries, req.RetryBackoffSeconds); err != nil {
+		return nil, err
+	}
 
-	return ds.ServerOps.CreateServer(req)
+	// The RCON password is only stored encrypted
+	stored := *req
+	password, err := secrets.Encrypt(req.RCONPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt RCON password: %w", err)
+	}
+	stored.RCONPassword = password
+
+	return ds.ServerOps.CreateServer(&stored)
 }
 
 // GetServer retrieves a server by ID
@@ -55,21 +107,637 @@ func (ds *DatabaseService) GetAllServers() ([]models.Server, error) {
 // UpdateServer updates a server with validation
 func (ds *DatabaseService) UpdateServer(id uint, req *models.UpdateServerRequest) (*models.Server, error) {
 	// Validate server type
-	if req.Type != "minecraft" && req.Type != "cs2" {
-		return nil, errors.New("invalid server type: must be 'minecraft' or 'cs2'")
+	serverType, ok := protocol.Lookup(req.Type)
+	if !ok {
+		return nil, fmt.Errorf("invalid server type: must be one of %s", protocol.Names())
 	}
 
-	// Validate port range
-	if req.Port < 1 || req.Port > 65535 {
+	// Validate port range; types located via SRV records may omit the port,
+	// other types fall back to their default port
+	if req.Port == 0 && serverType.SRVService == "" {
+		if serverType.DefaultPort == 0 {
+			return nil, fmt.Errorf("port is required for server type %s", req.Type)
+		}
+		req.Port = serverType.DefaultPort
+	}
+	if req.Port < 0 || req.Port > 65535 {
 		return nil, errors.New("invalid port: must be between 1 and 65535")
 	}
+	if req.QueryPort < 0 || req.QueryPort > 65535 {
+		return nil, errors.New("invalid query port: must be between 1 and 65535")
+	}
+	if req.RCONPort < 0 || req.RCONPort > 65535 {
+		return nil, errors.New("invalid RCON port: must be between 1 and 65535")
+	}
+	if serverType.ValidateCheck != nil {
+		if err := serverType.ValidateCheck(&req.Check); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateProbeSettings(req.ProbeIntervalSeconds, req.ProbeTimeoutSeconds, req.ProbeRetries, req.RetryBackoffSeconds); err != nil {
+		return nil, err
+	}
 
-	return ds.ServerOps.UpdateServer(id, req)
+	// The RCON password is only stored encrypted; an empty one keeps the current password
+	stored := *req
+	password, err := secrets.Encrypt(req.RCONPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt RCON password: %w", err)
+	}
+	stored.RCONPassword = password
+
+	return ds.ServerOps.UpdateServer(id, &stored)
 }
 
-// DeleteServer deletes a server
+// DeleteServer deletes a server, its recorded history, its alert states and its icon
 func (ds *DatabaseService) DeleteServer(id uint) error {
-	return ds.ServerOps.DeleteServer(id)
+	if err := ds.ServerOps.DeleteServer(id); err != nil {
+		return err
+	}
+
+	if err := ds.HistoryOps.DeleteSamplesForServer(id); err != nil {
+		log.Printf("Warning: Failed to delete history for server %d: %v", id, err)
+	}
+
+	if err := ds.AlertOps.DeleteStatesForServer(id); err != nil {
+		log.Printf("Warning: Failed to delete alert states for server %d: %v", id, err)
+	}
+
+	if err := ds.IconOps.Delete(id); err != nil {
+		log.Printf("Warning: Failed to delete icon for server %d: %v", id, err)
+	}
+
+	return nil
+}
+
+// Icon operations
+
+// SaveServerIcon stores the PNG icon of a server
+func (ds *DatabaseService) SaveServerIcon(serverID uint, png []byte) (*models.ServerIcon, error) {
+	if !bytes.HasPrefix(png, []byte("\x89PNG\r\n\x1a\n")) {
+		return nil, errors.New("server icon must be a PNG image")
+	}
+
+	hash := sha256.Sum256(png)
+	icon := &models.ServerIcon{
+		ServerID: serverID,
+		PNG:      png,
+		Hash:     hex.EncodeToString(hash[:]),
+	}
+	if err := ds.IconOps.Save(icon); err != nil {
+		return nil, err
+	}
+	return icon, nil
+}
+
+// GetServerIcon retrieves the last known icon of a server
+func (ds *DatabaseService) GetServerIcon(serverID uint) (*models.ServerIcon, error) {
+	return ds.IconOps.Get(serverID)
+}
+
+// GetServerIconHashes retrieves the icon hashes of all servers with an icon
+func (ds *DatabaseService) GetServerIconHashes() (map[uint]string, error) {
+	return ds.IconOps.GetHashes()
+}
+
+// GetRCONPassword decrypts the RCON password of a server, returning the
+// empty string if none is set
+func (ds *DatabaseService) GetRCONPassword(server *models.Server) (string, error) {
+	return secrets.Decrypt(server.RCONPassword)
+}
+
+// RCON audit operations
+
+// RecordRCONExecution adds an entry to the RCON audit log
+func (ds *DatabaseService) RecordRCONExecution(entry *models.RCONAuditEntry) error {
+	return ds.RCONOps.CreateAuditEntry(entry)
+}
+
+// GetRCONAuditLog retrieves the most recent RCON audit entries of a server, newest first
+func (ds *DatabaseService) GetRCONAuditLog(serverID uint, limit int) ([]models.RCONAuditEntry, error) {
+	return ds.RCONOps.GetAuditEntries(serverID, limit)
+}
+
+// History operations
+
+// RecordStatusSample persists a probe result for a server
+func (ds *DatabaseService) RecordStatusSample(serverID uint, status *models.ServerStatus) error {
+	return ds.HistoryOps.CreateSample(&models.StatusSample{
+		ServerID:    serverID,
+		Timestamp:   status.LastUpdated.UTC(),
+		Online:      status.Online,
+		Players:     status.Players,
+		MaxPlayers:  status.MaxPlayers,
+		Ping:        status.Ping,
+		Version:     status.Version,
+		Maintenance: status.Maintenance,
+	})
+}
+
+// GetStatusSamples retrieves the probe results of a server in [from, to)
+func (ds *DatabaseService) GetStatusSamples(serverID uint, from, to time.Time) ([]models.StatusSample, error) {
+	if !from.Before(to) {
+		return nil, errors.New("invalid time range: from must be before to")
+	}
+
+	return ds.HistoryOps.GetSamples(serverID, from, to)
+}
+
+// GetStatusRollups retrieves the rollups of a server and tier with bucket starts in [from, to)
+func (ds *DatabaseService) GetStatusRollups(serverID uint, tier string, from, to time.Time) ([]models.StatusRollup, error) {
+	if !from.Before(to) {
+		return nil, errors.New("invalid time range: from must be before to")
+	}
+
+	return ds.HistoryOps.GetRollups(serverID, tier, from, to)
+}
+
+// Notification operations
+
+// CreateNotificationChannel creates a new notification channel with validation
+func (ds *DatabaseService) CreateNotificationChannel(req *models.CreateNotificationChannelRequest) (*models.NotificationChannel, error) {
+	if err := validateEventTypes(req.Events); err != nil {
+		return nil, err
+	}
+	if err := validateTemplate(req.Template); err != nil {
+		return nil, err
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	channel := &models.NotificationChannel{
+		Name:         req.Name,
+		Type:         req.Type,
+		URL:          req.URL,
+		Secret:       req.Secret,
+		Events:       req.Events,
+		ServerIDs:    req.ServerIDs,
+		ServerGroups: req.ServerGroups,
+		Template:     req.Template,
+		Enabled:      enabled,
+		SMTPHost:     req.SMTPHost,
+		SMTPPort:     req.SMTPPort,
+		SMTPStartTLS: req.SMTPStartTLS,
+		SMTPUsername: req.SMTPUsername,
+		SMTPPassword: req.SMTPPassword,
+		EmailFrom:    req.EmailFrom,
+		EmailTo:      req.EmailTo,
+		Digest:       req.Digest,
+	}
+
+	if err := validateChannelTarget(channel); err != nil {
+		return nil, err
+	}
+	if err := encryptCredentials(channel); err != nil {
+		return nil, err
+	}
+
+	if err := ds.NotificationOps.CreateChannel(channel); err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// GetNotificationChannel retrieves a notification channel by ID
+func (ds *DatabaseService) GetNotificationChannel(id uint) (*models.NotificationChannel, error) {
+	return ds.NotificationOps.GetChannelByID(id)
+}
+
+// GetAllNotificationChannels retrieves all notification channels
+func (ds *DatabaseService) GetAllNotificationChannels() ([]models.NotificationChannel, error) {
+	return ds.NotificationOps.GetAllChannels()
+}
+
+// GetEnabledNotificationChannels retrieves all enabled notification channels
+func (ds *DatabaseService) GetEnabledNotificationChannels() ([]models.NotificationChannel, error) {
+	return ds.NotificationOps.GetEnabledChannels()
+}
+
+// UpdateNotificationChannel updates a notification channel with validation.
+// An empty secret or SMTP password keeps the current one.
+func (ds *DatabaseService) UpdateNotificationChannel(id uint, req *models.UpdateNotificationChannelRequest) (*models.NotificationChannel, error) {
+	if err := validateEventTypes(req.Events); err != nil {
+		return nil, err
+	}
+	if err := validateTemplate(req.Template); err != nil {
+		return nil, err
+	}
+
+	channel, err := ds.NotificationOps.GetChannelByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	channel.Name = req.Name
+	channel.Type = req.Type
+	channel.URL = req.URL
+	channel.Events = req.Events
+	channel.ServerIDs = req.ServerIDs
+	channel.ServerGroups = req.ServerGroups
+	channel.Template = req.Template
+	channel.Enabled = true
+	if req.Enabled != nil {
+		channel.Enabled = *req.Enabled
+	}
+	if req.Secret != "" {
+		channel.Secret = req.Secret
+	}
+	channel.SMTPHost = req.SMTPHost
+	channel.SMTPPort = req.SMTPPort
+	channel.SMTPStartTLS = req.SMTPStartTLS
+	channel.SMTPUsername = req.SMTPUsername
+	if req.SMTPPassword != "" {
+		channel.SMTPPassword = req.SMTPPassword
+	}
+	channel.EmailFrom = req.EmailFrom
+	channel.EmailTo = req.EmailTo
+	channel.Digest = req.Digest
+
+	if err := validateChannelTarget(channel); err != nil {
+		return nil, err
+	}
+	if err := encryptCredentials(channel); err != nil {
+		return nil, err
+	}
+
+	if err := ds.NotificationOps.SaveChannel(channel); err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// encryptCredentials encrypts the webhook secret and SMTP password of a
+// channel; values that are already encrypted are kept
+func encryptCredentials(channel *models.NotificationChannel) error {
+	for _, value := range []*string{&channel.Secret, &channel.SMTPPassword} {
+		if secrets.IsEncrypted(*value) {
+			continue
+		}
+		encrypted, err := secrets.Encrypt(*value)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt channel credentials: %w", err)
+		}
+		*value = encrypted
+	}
+	return nil
+}
+
+// DeleteNotificationChannel deletes a notification channel
+func (ds *DatabaseService) DeleteNotificationChannel(id uint) error {
+	return ds.NotificationOps.DeleteChannel(id)
+}
+
+// RecordNotificationDelivery stores the outcome of a notification delivery
+func (ds *DatabaseService) RecordNotificationDelivery(delivery *models.NotificationDelivery) error {
+	return ds.NotificationOps.CreateDelivery(delivery)
+}
+
+// GetNotificationDeliveries retrieves the most recent deliveries of a channel
+func (ds *DatabaseService) GetNotificationDeliveries(channelID uint, limit int) ([]models.NotificationDelivery, error) {
+	return ds.NotificationOps.GetDeliveries(channelID, limit)
+}
+
+// Alert operations
+
+// CreateAlertRule creates a new alert rule with validation
+func (ds *DatabaseService) CreateAlertRule(req *models.CreateAlertRuleRequest) (*models.AlertRule, error) {
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	rule := &models.AlertRule{
+		Name:              req.Name,
+		Enabled:           enabled,
+		ServerIDs:         req.ServerIDs,
+		ServerGroups:      req.ServerGroups,
+		ServerTypes:       req.ServerTypes,
+		Metric:            req.Metric,
+		Comparator:        req.Comparator,
+		Threshold:         req.Threshold,
+		ForSeconds:        req.ForSeconds,
+		ConsecutiveProbes: req.ConsecutiveProbes,
+		ActiveFrom:        req.ActiveFrom,
+		ActiveTo:          req.ActiveTo,
+	}
+
+	if err := validateAlertRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := ds.AlertOps.CreateRule(rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// GetAlertRule retrieves an alert rule by ID
+func (ds *DatabaseService) GetAlertRule(id uint) (*models.AlertRule, error) {
+	return ds.AlertOps.GetRuleByID(id)
+}
+
+// GetAllAlertRules retrieves all alert rules
+func (ds *DatabaseService) GetAllAlertRules() ([]models.AlertRule, error) {
+	return ds.AlertOps.GetAllRules()
+}
+
+// GetEnabledAlertRules retrieves all enabled alert rules
+func (ds *DatabaseService) GetEnabledAlertRules() ([]models.AlertRule, error) {
+	return ds.AlertOps.GetEnabledRules()
+}
+
+// UpdateAlertRule updates an alert rule with validation. The states of the
+// rule are not reset; the next evaluation applies the new condition.
+func (ds *DatabaseService) UpdateAlertRule(id uint, req *models.UpdateAlertRuleRequest) (*models.AlertRule, error) {
+	rule, err := ds.AlertOps.GetRuleByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.Name = req.Name
+	rule.Enabled = true
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	rule.ServerIDs = req.ServerIDs
+	rule.ServerGroups = req.ServerGroups
+	rule.ServerTypes = req.ServerTypes
+	rule.Metric = req.Metric
+	rule.Comparator = req.Comparator
+	rule.Threshold = req.Threshold
+	rule.ForSeconds = req.ForSeconds
+	rule.ConsecutiveProbes = req.ConsecutiveProbes
+	rule.ActiveFrom = req.ActiveFrom
+	rule.ActiveTo = req.ActiveTo
+
+	if err := validateAlertRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := ds.AlertOps.SaveRule(rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// DeleteAlertRule deletes an alert rule and its states
+func (ds *DatabaseService) DeleteAlertRule(id uint) error {
+	return ds.AlertOps.DeleteRule(id)
+}
+
+// GetAlertStates retrieves alert states, optionally filtered by state
+func (ds *DatabaseService) GetAlertStates(state string) ([]models.AlertState, error) {
+	return ds.AlertOps.GetStates(state)
+}
+
+// SaveAlertState inserts or updates the state of a rule for a server
+func (ds *DatabaseService) SaveAlertState(state *models.AlertState) error {
+	return ds.AlertOps.SaveState(state)
+}
+
+// DeleteAlertState deletes the state of a rule for a server
+func (ds *DatabaseService) DeleteAlertState(ruleID, serverID uint) error {
+	return ds.AlertOps.DeleteState(ruleID, serverID)
+}
+
+// Maintenance window operations
+
+// CreateMaintenanceWindow creates a new maintenance window with validation
+func (ds *DatabaseService) CreateMaintenanceWindow(req *models.CreateMaintenanceWindowRequest) (*models.MaintenanceWindow, error) {
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	window := &models.MaintenanceWindow{
+		Title:           req.Title,
+		Description:     req.Description,
+		ServerIDs:       req.ServerIDs,
+		StartsAt:        req.StartsAt,
+		EndsAt:          req.EndsAt,
+		Cron:            req.Cron,
+		DurationMinutes: req.DurationMinutes,
+		Timezone:        req.Timezone,
+		Enabled:         enabled,
+	}
+
+	if err := validateMaintenanceWindow(window); err != nil {
+		return nil, err
+	}
+
+	if err := ds.MaintenanceOps.Create(window); err != nil {
+		return nil, err
+	}
+
+	return window, nil
+}
+
+// GetMaintenanceWindow retrieves a maintenance window by ID
+func (ds *DatabaseService) GetMaintenanceWindow(id uint) (*models.MaintenanceWindow, error) {
+	return ds.MaintenanceOps.GetByID(id)
+}
+
+// GetAllMaintenanceWindows retrieves all maintenance windows
+func (ds *DatabaseService) GetAllMaintenanceWindows() ([]models.MaintenanceWindow, error) {
+	return ds.MaintenanceOps.GetAll()
+}
+
+// GetEnabledMaintenanceWindows retrieves all enabled maintenance windows
+func (ds *DatabaseService) GetEnabledMaintenanceWindows() ([]models.MaintenanceWindow, error) {
+	return ds.MaintenanceOps.GetEnabled()
+}
+
+// UpdateMaintenanceWindow updates a maintenance window with validation
+func (ds *DatabaseService) UpdateMaintenanceWindow(id uint, req *models.UpdateMaintenanceWindowRequest) (*models.MaintenanceWindow, error) {
+	window, err := ds.MaintenanceOps.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	window.Title = req.Title
+	window.Description = req.Description
+	window.ServerIDs = req.ServerIDs
+	window.StartsAt = req.StartsAt
+	window.EndsAt = req.EndsAt
+	window.Cron = req.Cron
+	window.DurationMinutes = req.DurationMinutes
+	window.Timezone = req.Timezone
+	window.Enabled = true
+	if req.Enabled != nil {
+		window.Enabled = *req.Enabled
+	}
+
+	if err := validateMaintenanceWindow(window); err != nil {
+		return nil, err
+	}
+
+	if err := ds.MaintenanceOps.Save(window); err != nil {
+		return nil, err
+	}
+
+	return window, nil
+}
+
+// DeleteMaintenanceWindow deletes a maintenance window
+func (ds *DatabaseService) DeleteMaintenanceWindow(id uint) error {
+	return ds.MaintenanceOps.Delete(id)
+}
+
+// validateProbeSettings checks a server's probe schedule and retry policy; 0 selects the default
+func validateProbeSettings(intervalSeconds, timeoutSeconds, retries, backoffSeconds int) error {
+	if intervalSeconds != 0 && (intervalSeconds < 5 || intervalSeconds > 86400) {
+		return errors.New("invalid probe interval: must be between 5 and 86400 seconds")
+	}
+	if timeoutSeconds != 0 && (timeoutSeconds < 1 || timeoutSeconds > 60) {
+		return errors.New("invalid probe timeout: must be between 1 and 60 seconds")
+	}
+	if intervalSeconds != 0 && timeoutSeconds > intervalSeconds {
+		return errors.New("invalid probe timeout: must not exceed the probe interval")
+	}
+	if retries < 0 || retries > 10 {
+		return errors.New("invalid probe retries: must be between 1 and 10")
+	}
+	if backoffSeconds < 0 || backoffSeconds > 300 {
+		return errors.New("invalid retry backoff: must be between 1 and 300 seconds")
+	}
+	return nil
+}
+
+// validateMaintenanceWindow checks that a window is either one-off or recurring
+func validateMaintenanceWindow(window *models.MaintenanceWindow) error {
+	oneOff := window.StartsAt != nil || window.EndsAt != nil
+	recurring := window.Cron != "" || window.DurationMinutes != 0
+
+	switch {
+	case oneOff && recurring:
+		return errors.New("a maintenance window is either one-off (starts_at, ends_at) or recurring (cron, duration_minutes), not both")
+	case oneOff:
+		if window.StartsAt == nil || window.EndsAt == nil {
+			return errors.New("starts_at and ends_at must be set together")
+		}
+		if !window.EndsAt.After(*window.StartsAt) {
+			return errors.New("ends_at must be after starts_at")
+		}
+		if window.Timezone != "" {
+			return errors.New("timezone only applies to recurring windows")
+		}
+	case recurring:
+		if _, err := cron.Parse(window.Cron); err != nil {
+			return fmt.Errorf("invalid cron expression: %w", err)
+		}
+		if window.DurationMinutes <= 0 {
+			return errors.New("duration_minutes must be positive for recurring windows")
+		}
+		if window.Timezone != "" {
+			if _, err := time.LoadLocation(window.Timezone); err != nil {
+				return fmt.Errorf("invalid timezone: %s", window.Timezone)
+			}
+		}
+	default:
+		return errors.New("either starts_at and ends_at or cron and duration_minutes are required")
+	}
+
+	return nil
+}
+
+// validateAlertRule checks the metric, comparator, server types and active window of a rule
+func validateAlertRule(rule *models.AlertRule) error {
+	switch rule.Metric {
+	case models.MetricOnline, models.MetricPlayers, models.MetricMaxPlayers,
+		models.MetricPlayerPercent, models.MetricPing:
+	default:
+		return fmt.Errorf("invalid metric: %s", rule.Metric)
+	}
+
+	switch rule.Comparator {
+	case models.ComparatorGreater, models.ComparatorGreaterEqual, models.ComparatorLess,
+		models.ComparatorLessEqual, models.ComparatorEqual, models.ComparatorNotEqual:
+	default:
+		return fmt.Errorf("invalid comparator: %s", rule.Comparator)
+	}
+
+	for _, t := range rule.ServerTypes {
+		if !protocol.IsValid(t) {
+			return fmt.Errorf("invalid server type: %s", t)
+		}
+	}
+
+	if rule.ForSeconds < 0 || rule.ConsecutiveProbes < 0 {
+		return errors.New("for_seconds and consecutive_probes must not be negative")
+	}
+
+	if (rule.ActiveFrom == "") != (rule.ActiveTo == "") {
+		return errors.New("active_from and active_to must be set together")
+	}
+	for _, t := range []string{rule.ActiveFrom, rule.ActiveTo} {
+		if t == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", t); err != nil {
+			return fmt.Errorf("invalid time of day %q: must be HH:MM", t)
+		}
+	}
+
+	return nil
+}
+
+// validateEventTypes checks that all given event types are known
+func validateEventTypes(types []string) error {
+	for _, t := range types {
+		if !events.IsValidType(events.Type(t)) {
+			return fmt.Errorf("invalid event type: %s", t)
+		}
+	}
+	return nil
+}
+
+// validateChannelTarget checks that a channel has the settings its type needs
+// and fills in the default SMTP port
+func validateChannelTarget(channel *models.NotificationChannel) error {
+	if channel.Type != models.ChannelTypeEmail {
+		if channel.URL == "" {
+			return errors.New("url is required for " + channel.Type + " channels")
+		}
+		if channel.Digest != "" {
+			return errors.New("digest is only supported by email channels")
+		}
+		return nil
+	}
+
+	if channel.SMTPHost == "" {
+		return errors.New("smtp_host is required for email channels")
+	}
+	if channel.EmailFrom == "" {
+		return errors.New("email_from is required for email channels")
+	}
+	if len(channel.EmailTo) == 0 {
+		return errors.New("email_to must contain at least one recipient")
+	}
+	if channel.Digest != "" && channel.Digest != models.DigestHourly && channel.Digest != models.DigestDaily {
+		return errors.New("invalid digest: must be 'hourly' or 'daily'")
+	}
+	if channel.SMTPPort == 0 {
+		channel.SMTPPort = 587
+	}
+	return nil
+}
+
+// validateTemplate checks that a message template parses
+func validateTemplate(text string) error {
+	if text == "" {
+		return nil
+	}
+	if _, err := template.New("message").Parse(text); err != nil {
+		return fmt.Errorf("invalid message template: %w", err)
+	}
+	return nil
 }
 
 // User operations with password hashing
@@ -0,0 +1,145 @@
+package events
+
+import (
+	"log"
+	"sync"
+)
+
+// subscriberBufferSize is the number of events queued per subscriber
+const subscriberBufferSize = 100
+
+// Handler receives published events
+type Handler func(event Event)
+
+// subscriber is a registered handler with its own delivery queue
+type subscriber struct {
+	name    string
+	handler Handler
+	types   map[Type]bool // Empty means all types
+	queue   chan Event
+	done    chan struct{}
+}
+
+// Bus is an in-process publish/subscribe event bus. Every subscriber gets its
+// own queue and goroutine so a slow subscriber never blocks the publisher.
+type Bus struct {
+	subscribers map[int]*subscriber
+	nextID      int
+	closed      bool
+	mutex       sync.RWMutex
+}
+
+// NewBus creates a new event bus
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[int]*subscriber),
+	}
+}
+
+// Subscribe registers a handler for the given event types (all types when
+// none are given) and returns a function that removes the subscription
+func (b *Bus) Subscribe(name string, handler Handler, types ...Type) func() {
+	sub := &subscriber{
+		name:    name,
+		handler: handler,
+		types:   make(map[Type]bool),
+		queue:   make(chan Event, subscriberBufferSize),
+		done:    make(chan struct{}),
+	}
+	for _, t := range types {
+		sub.types[t] = true
+	}
+
+	b.mutex.Lock()
+	if b.closed {
+		b.mutex.Unlock()
+		return func() {}
+	}
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = sub
+	b.mutex.Unlock()
+
+	go sub.run()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mutex.Lock()
+			if _, exists := b.subscribers[id]; exists {
+				delete(b.subscribers, id)
+				close(sub.queue)
+			}
+			b.mutex.Unlock()
+			<-sub.done
+		})
+	}
+}
+
+// Publish delivers an event to all interested subscribers. Events are
+// dropped for subscribers whose queue is full.
+func (b *Bus) Publish(event Event) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for _, sub := range b.subscribers {
+		if len(sub.types) > 0 && !sub.types[event.Type] {
+			continue
+		}
+
+		select {
+		case sub.queue <- event:
+		default:
+			log.Printf("Event queue of subscriber %s is full, dropping %s event for server %s",
+				sub.name, event.Type, event.Server.Name)
+		}
+	}
+}
+
+// SubscriberCount returns the number of active subscriptions
+func (b *Bus) SubscriberCount() int {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return len(b.subscribers)
+}
+
+// Close removes all subscriptions after their queued events were handled
+func (b *Bus) Close() {
+	b.mutex.Lock()
+	if b.closed {
+		b.mutex.Unlock()
+		return
+	}
+	b.closed = true
+	subscribers := b.subscribers
+	b.subscribers = make(map[int]*subscriber)
+	for _, sub := range subscribers {
+		close(sub.queue)
+	}
+	b.mutex.Unlock()
+
+	for _, sub := range subscribers {
+		<-sub.done
+	}
+}
+
+// run delivers queued events to the handler until the queue is closed
+func (s *subscriber) run() {
+	defer close(s.done)
+
+	for event := range s.queue {
+		s.deliver(event)
+	}
+}
+
+// deliver calls the handler, recovering from panics so one bad event does
+// not stop the subscription
+func (s *subscriber) deliver(event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Event subscriber %s panicked handling %s event: %v", s.name, event.Type, r)
+		}
+	}()
+
+	s.handler(event)
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"game-server-monitor/internal/models"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBus_PublishSubscribe(t *testing.T) {
+	bus := NewBus()
+	defer bus.Close()
+
+	var mutex sync.Mutex
+	var all, offline []Event
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	bus.Subscribe("all", func(event Event) {
+		mutex.Lock()
+		all = append(all, event)
+		mutex.Unlock()
+		wg.Done()
+	})
+	bus.Subscribe("offline", func(event Event) {
+		mutex.Lock()
+		offline = append(offline, event)
+		mutex.Unlock()
+		wg.Done()
+	}, TypeWentOffline)
+
+	server := models.Server{ID: 1, Name: "Test Server"}
+	bus.Publish(Event{Type: TypeWentOffline, Server: server})
+	bus.Publish(Event{Type: TypeWentOnline, Server: server})
+
+	waitTimeout(t, &wg)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if len(all) != 2 {
+		t.Errorf("Expected unfiltered subscriber to receive 2 events, got %d", len(all))
+	}
+	if len(offline) != 1 || offline[0].Type != TypeWentOffline {
+		t.Errorf("Expected filtered subscriber to receive only the offline event, got %+v", offline)
+	}
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	bus := NewBus()
+	defer bus.Close()
+
+	received := make(chan Event, 10)
+	unsubscribe := bus.Subscribe("test", func(event Event) {
+		received <- event
+	})
+
+	if bus.SubscriberCount() != 1 {
+		t.Fatalf("Expected 1 subscriber, got %d", bus.SubscriberCount())
+	}
+
+	unsubscribe()
+	unsubscribe() // Must be safe to call twice
+
+	if bus.SubscriberCount() != 0 {
+		t.Errorf("Expected no subscribers after unsubscribe, got %d", bus.SubscriberCount())
+	}
+
+	bus.Publish(Event{Type: TypeWentOnline})
+
+	select {
+	case event := <-received:
+		t.Errorf("Expected no events after unsubscribe, got %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestBus_HandlerPanicDoesNotStopSubscription(t *testing.T) {
+	bus := NewBus()
+	defer bus.Close()
+
+	received := make(chan Event, 10)
+	bus.Subscribe("flaky", func(event Event) {
+		if event.Type == TypeWentOffline {
+			panic("boom")
+		}
+		received <- event
+	})
+
+	bus.Publish(Event{Type: TypeWentOffline})
+	bus.Publish(Event{Type: TypeWentOnline})
+
+	select {
+	case event := <-received:
+		if event.Type != TypeWentOnline {
+			t.Errorf("Expected went_online event, got %s", event.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected subscriber to keep receiving events after a panic")
+	}
+}
+
+func waitTimeout(t *testing.T, wg *sync.WaitGroup) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for events")
+	}
+}
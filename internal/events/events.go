@@ -0,0 +1,65 @@
+package events
+
+import (
+	"game-server-monitor/internal/models"
+	"time"
+)
+
+// Type identifies the kind of a server state-change event
+type Type string
+
+const (
+	TypeWentOnline             Type = "went_online"
+	TypeWentOffline            Type = "went_offline"
+	TypeVersionChanged         Type = "version_changed"
+	TypePlayerThresholdCrossed Type = "player_threshold_crossed"
+	TypePingDegraded           Type = "ping_degraded"
+	TypeAlertFiring            Type = "alert_firing"
+	TypeAlertResolved          Type = "alert_resolved"
+	TypeFlapStart              Type = "flap_start"
+	TypeFlapEnd                Type = "flap_end"
+)
+
+// Threshold crossing directions
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
+)
+
+// AllTypes lists every event type published by the monitor
+var AllTypes = []Type{
+	TypeWentOnline,
+	TypeWentOffline,
+	TypeVersionChanged,
+	TypePlayerThresholdCrossed,
+	TypePingDegraded,
+	TypeAlertFiring,
+	TypeAlertResolved,
+	TypeFlapStart,
+	TypeFlapEnd,
+}
+
+// Event describes a change between two consecutive statuses of a server, or
+// an alert rule changing state for a server
+type Event struct {
+	Type      Type                 `json:"type"`
+	Server    models.Server        `json:"server"`
+	Previous  *models.ServerStatus `json:"previous"`
+	Current   *models.ServerStatus `json:"current"`
+	Timestamp time.Time            `json:"timestamp"`
+	Message   string               `json:"message"`             // Human readable summary
+	Threshold float64              `json:"threshold,omitempty"` // Threshold that was crossed, if any
+	Direction string               `json:"direction,omitempty"` // "up" or "down" for threshold crossings
+	Rule      *models.AlertRule    `json:"rule,omitempty"`      // Alert rule of alert events
+	Value     float64              `json:"value,omitempty"`     // Metric value of alert events
+}
+
+// IsValidType reports whether t is a known event type
+func IsValidType(t Type) bool {
+	for _, known := range AllTypes {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
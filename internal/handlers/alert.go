@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"game-server-monitor/internal/auth"
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AlertHandler handles alert rule management requests
+type AlertHandler struct {
+	dbService *database.DatabaseService
+}
+
+// NewAlertHandler creates a new AlertHandler instance
+func NewAlertHandler() *AlertHandler {
+	return &AlertHandler{
+		dbService: database.NewDatabaseService(),
+	}
+}
+
+// GetRules returns all alert rules
+// GET /api/admin/alerts
+func (h *AlertHandler) GetRules(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	rules, err := h.dbService.GetAllAlertRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve alert rules",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": rules,
+	})
+}
+
+// CreateRule creates a new alert rule
+// POST /api/admin/alerts
+func (h *AlertHandler) CreateRule(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var req models.CreateAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.dbService.CreateAlertRule(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Alert rule creation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":    rule,
+		"message": "Alert rule created successfully",
+	})
+}
+
+// UpdateRule updates an existing alert rule
+// PUT /api/admin/alerts/:id
+func (h *AlertHandler) UpdateRule(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ruleID, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.dbService.UpdateAlertRule(ruleID, &req)
+	if err != nil {
+		if err.Error() == "alert rule not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Alert rule not found",
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Alert rule update failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    rule,
+		"message": "Alert rule updated successfully",
+	})
+}
+
+// DeleteRule deletes an alert rule and its states
+// DELETE /api/admin/alerts/:id
+func (h *AlertHandler) DeleteRule(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	ruleID, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.dbService.DeleteAlertRule(ruleID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Alert rule deletion failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Alert rule deleted successfully",
+	})
+}
+
+// GetStates returns the alert states of all rules and servers
+// GET /api/admin/alerts/states?state=firing
+func (h *AlertHandler) GetStates(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	state := c.Query("state")
+	switch state {
+	case "", models.AlertStateInactive, models.AlertStatePending, models.AlertStateFiring, models.AlertStateResolved:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid state",
+			"message": "State must be one of inactive, pending, firing, resolved",
+		})
+		return
+	}
+
+	states, err := h.dbService.GetAlertStates(state)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve alert states",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": states,
+	})
+}
+
+// parseRuleID parses the rule ID URL parameter, writing a 400 response on failure
+func parseRuleID(c *gin.Context) (uint, bool) {
+	ruleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid rule ID",
+			"message": "Rule ID must be a valid number",
+		})
+		return 0, false
+	}
+	return uint(ruleID), true
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/history"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HistoryHandler handles status history requests
+type HistoryHandler struct {
+	dbService      *database.DatabaseService
+	historyService *history.Service
+}
+
+// NewHistoryHandler creates a new HistoryHandler instance
+func NewHistoryHandler(historyService *history.Service) *HistoryHandler {
+	return &HistoryHandler{
+		dbService:      database.NewDatabaseService(),
+		historyService: historyService,
+	}
+}
+
+// GetServerHistory returns the bucketed status series of a server
+// GET /api/servers/:id/history?from=&to=&step=
+func (h *HistoryHandler) GetServerHistory(c *gin.Context) {
+	// Parse server ID from URL parameter
+	serverIDStr := c.Param("id")
+	serverID, err := strconv.ParseUint(serverIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid server ID",
+			"message": "Server ID must be a valid number",
+		})
+		return
+	}
+
+	from, to, err := parseTimeRange(c, history.DefaultRange)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid time range",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	step, err := parseStep(c.Query("step"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid step",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// Make sure the server exists before querying its history
+	if _, err := h.dbService.GetServer(uint(serverID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Server not found",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.historyService.GetHistory(uint(serverID), from, to, step)
+	if err != nil {
+		c.JSON(queryErrorStatus(err), gin.H{
+			"error":   "Failed to retrieve history",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": result,
+	})
+}
+
+// GetServerUptime returns the uptime percentage and outage incidents of a server
+// GET /api/servers/:id/uptime?window=24h|7d|30d|90d or ?from=&to=
+func (h *HistoryHandler) GetServerUptime(c *gin.Context) {
+	// Parse server ID from URL parameter
+	serverIDStr := c.Param("id")
+	serverID, err := strconv.ParseUint(serverIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid server ID",
+			"message": "Server ID must be a valid number",
+		})
+		return
+	}
+
+	var from, to time.Time
+	if window := c.Query("window"); window != "" {
+		length, ok := history.UptimeWindows[window]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid window",
+				"message": "Window must be one of 24h, 7d, 30d or 90d",
+			})
+			return
+		}
+		to = time.Now()
+		from = to.Add(-length)
+	} else {
+		from, to, err = parseTimeRange(c, history.UptimeWindows["24h"])
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid time range",
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
+	server, err := h.dbService.GetServer(uint(serverID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Server not found",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	report, err := h.historyService.Uptime(server, from, to)
+	if err != nil {
+		c.JSON(queryErrorStatus(err), gin.H{
+			"error":   "Failed to calculate uptime",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// queryErrorStatus returns the HTTP status of a failed history query: 400 for
+// an invalid range or step, 500 for any other failure
+func queryErrorStatus(err error) int {
+	var queryErr *history.QueryError
+	if errors.As(err, &queryErr) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// parseTimeRange reads the from/to query parameters, defaulting to the
+// last defaultRange ending now
+func parseTimeRange(c *gin.Context, defaultRange time.Duration) (time.Time, time.Time, error) {
+	to := time.Now()
+	if value := c.Query("to"); value != "" {
+		parsed, err := parseTimeParam(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = parsed
+	}
+
+	from := to.Add(-defaultRange)
+	if value := c.Query("from"); value != "" {
+		parsed, err := parseTimeParam(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("from must be before to")
+	}
+
+	return from, to, nil
+}
+
+// parseTimeParam accepts RFC 3339 timestamps or Unix seconds
+func parseTimeParam(value string) (time.Time, error) {
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("time must be RFC 3339 or Unix seconds")
+	}
+	return parsed, nil
+}
+
+// parseStep accepts Go durations ("5m") or plain seconds; empty means auto
+func parseStep(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds <= 0 {
+			return 0, errors.New("step must be positive")
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	step, err := time.ParseDuration(value)
+	if err != nil || step <= 0 {
+		return 0, errors.New("step must be a positive duration (e.g. 5m) or seconds")
+	}
+	return step, nil
+}
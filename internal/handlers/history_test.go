@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/history"
+	"game-server-monitor/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryHandler_GetServerHistory(t *testing.T) {
+	// Initialize test database
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+
+	dbService := database.NewDatabaseService()
+	server, err := dbService.CreateServer(&models.CreateServerRequest{
+		Name:    "History Server",
+		Type:    "tcp",
+		Address: "127.0.0.1",
+		Port:    25565,
+	})
+	if err != nil {
+		t.Fatal("Failed to create server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	handler := NewHistoryHandler(history.NewService(dbService))
+
+	// Setup Gin router
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/servers/:id/history", handler.GetServerHistory)
+
+	path := "/api/servers/" + strconv.Itoa(int(server.ID)) + "/history"
+	tests := []struct {
+		name   string
+		url    string
+		status int
+		error  string
+	}{
+		{"invalid server ID", "/api/servers/invalid/history", http.StatusBadRequest, "Invalid server ID"},
+		{"invalid from", path + "?from=yesterday", http.StatusBadRequest, "Invalid time range"},
+		{"invalid to", path + "?to=2024-13-01", http.StatusBadRequest, "Invalid time range"},
+		{"from after to", path + "?from=1714572000&to=1714568400", http.StatusBadRequest, "Invalid time range"},
+		{"invalid step", path + "?step=often", http.StatusBadRequest, "Invalid step"},
+		{"negative step", path + "?step=-60", http.StatusBadRequest, "Invalid step"},
+		{"step below minimum", path + "?step=1s", http.StatusBadRequest, "Failed to retrieve history"},
+		{"too many points", path + "?from=1714521600&to=1714608000&step=10", http.StatusBadRequest, "Failed to retrieve history"},
+		{"unknown server", "/api/servers/999999/history", http.StatusNotFound, "Server not found"},
+		{"default range", path, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", tt.url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.status, w.Code, tt.name)
+
+		var response map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err, tt.name)
+		if tt.error != "" {
+			assert.Equal(t, tt.error, response["error"], tt.name)
+		}
+	}
+
+	// Unix seconds and RFC 3339 timestamps are both accepted, and plain
+	// seconds and durations as the step
+	from := time.Now().Add(-2 * time.Hour).Truncate(time.Hour)
+	to := from.Add(time.Hour)
+	for _, query := range []string{
+		fmt.Sprintf("?from=%d&to=%d&step=300", from.Unix(), to.Unix()),
+		fmt.Sprintf("?from=%s&to=%s&step=5m", from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339)),
+	} {
+		req, _ := http.NewRequest("GET", path+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, query)
+
+		var response struct {
+			Data models.HistoryResponse `json:"data"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err, query)
+		assert.Equal(t, server.ID, response.Data.ServerID, query)
+		assert.Equal(t, int64(300), response.Data.Step, query)
+		assert.Len(t, response.Data.Points, 12, query)
+		assert.True(t, response.Data.From.Equal(from), query)
+	}
+
+	// Failures reading the stored history are server errors
+	if err := database.DB.Migrator().DropTable(&models.StatusSample{}); err != nil {
+		t.Fatal("Failed to drop samples:", err)
+	}
+	defer database.DB.AutoMigrate(&models.StatusSample{})
+
+	req, _ := http.NewRequest("GET", path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Failed to retrieve history", response["error"])
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"game-server-monitor/internal/auth"
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceHandler handles maintenance window management requests
+type MaintenanceHandler struct {
+	dbService *database.DatabaseService
+}
+
+// NewMaintenanceHandler creates a new MaintenanceHandler instance
+func NewMaintenanceHandler() *MaintenanceHandler {
+	return &MaintenanceHandler{
+		dbService: database.NewDatabaseService(),
+	}
+}
+
+// GetWindows returns all maintenance windows
+// GET /api/admin/maintenance
+func (h *MaintenanceHandler) GetWindows(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	windows, err := h.dbService.GetAllMaintenanceWindows()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve maintenance windows",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": windows,
+	})
+}
+
+// CreateWindow creates a new maintenance window
+// POST /api/admin/maintenance
+func (h *MaintenanceHandler) CreateWindow(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var req models.CreateMaintenanceWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	window, err := h.dbService.CreateMaintenanceWindow(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Maintenance window creation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":    window,
+		"message": "Maintenance window created successfully",
+	})
+}
+
+// UpdateWindow updates an existing maintenance window
+// PUT /api/admin/maintenance/:id
+func (h *MaintenanceHandler) UpdateWindow(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	windowID, ok := parseWindowID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateMaintenanceWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	window, err := h.dbService.UpdateMaintenanceWindow(windowID, &req)
+	if err != nil {
+		if err.Error() == "maintenance window not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Maintenance window not found",
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Maintenance window update failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    window,
+		"message": "Maintenance window updated successfully",
+	})
+}
+
+// DeleteWindow deletes a maintenance window
+// DELETE /api/admin/maintenance/:id
+func (h *MaintenanceHandler) DeleteWindow(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	windowID, ok := parseWindowID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.dbService.DeleteMaintenanceWindow(windowID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Maintenance window deletion failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Maintenance window deleted successfully",
+	})
+}
+
+// parseWindowID parses the window ID URL parameter, writing a 400 response on failure
+func parseWindowID(c *gin.Context) (uint, bool) {
+	windowID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid window ID",
+			"message": "Window ID must be a valid number",
+		})
+		return 0, false
+	}
+	return uint(windowID), true
+}
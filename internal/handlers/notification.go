@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"game-server-monitor/internal/auth"
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/models"
+	"game-server-monitor/internal/notify"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// NotificationHandler handles notification channel management requests
+type NotificationHandler struct {
+	dbService  *database.DatabaseService
+	dispatcher *notify.Dispatcher
+}
+
+// NewNotificationHandler creates a new NotificationHandler instance
+func NewNotificationHandler(dispatcher *notify.Dispatcher) *NotificationHandler {
+	return &NotificationHandler{
+		dbService:  database.NewDatabaseService(),
+		dispatcher: dispatcher,
+	}
+}
+
+// GetChannels returns all notification channels
+// GET /api/admin/notifications
+func (h *NotificationHandler) GetChannels(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	channels, err := h.dbService.GetAllNotificationChannels()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve notification channels",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": channels,
+	})
+}
+
+// CreateChannel creates a new notification channel
+// POST /api/admin/notifications
+func (h *NotificationHandler) CreateChannel(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var req models.CreateNotificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	channel, err := h.dbService.CreateNotificationChannel(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Notification channel creation failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":    channel,
+		"message": "Notification channel created successfully",
+	})
+}
+
+// UpdateChannel updates an existing notification channel
+// PUT /api/admin/notifications/:id
+func (h *NotificationHandler) UpdateChannel(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	channelID, ok := parseChannelID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateNotificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	channel, err := h.dbService.UpdateNotificationChannel(channelID, &req)
+	if err != nil {
+		if err.Error() == "notification channel not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Notification channel not found",
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Notification channel update failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    channel,
+		"message": "Notification channel updated successfully",
+	})
+}
+
+// DeleteChannel deletes a notification channel and its delivery log
+// DELETE /api/admin/notifications/:id
+func (h *NotificationHandler) DeleteChannel(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	channelID, ok := parseChannelID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.dbService.DeleteNotificationChannel(channelID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Notification channel deletion failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Notification channel deleted successfully",
+	})
+}
+
+// GetDeliveries returns the delivery log of a notification channel
+// GET /api/admin/notifications/:id/deliveries?limit=50
+func (h *NotificationHandler) GetDeliveries(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	channelID, ok := parseChannelID(c)
+	if !ok {
+		return
+	}
+
+	limit := defaultDeliveryLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > maxDeliveryLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid limit",
+				"message": "Limit must be a number between 1 and 500",
+			})
+			return
+		}
+	}
+
+	if _, err := h.dbService.GetNotificationChannel(channelID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Notification channel not found",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	deliveries, err := h.dbService.GetNotificationDeliveries(channelID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve deliveries",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": deliveries,
+	})
+}
+
+// TestChannel sends a test notification to a channel
+// POST /api/admin/notifications/:id/test
+func (h *NotificationHandler) TestChannel(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	channelID, ok := parseChannelID(c)
+	if !ok {
+		return
+	}
+
+	channel, err := h.dbService.GetNotificationChannel(channelID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Notification channel not found",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	responseCode, err := h.dispatcher.SendTest(channel)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Test notification failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"response_code": responseCode,
+		},
+		"message": "Test notification sent successfully",
+	})
+}
+
+// parseChannelID parses the channel ID URL parameter, writing a 400 response on failure
+func parseChannelID(c *gin.Context) (uint, bool) {
+	channelID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid channel ID",
+			"message": "Channel ID must be a valid number",
+		})
+		return 0, false
+	}
+	return uint(channelID), true
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"game-server-monitor/internal/auth"
+	"game-server-monitor/internal/models"
+	"game-server-monitor/internal/prober"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProberHandler lets admins operate the background prober at runtime
+type ProberHandler struct {
+	proberService *prober.ProberService
+}
+
+// NewProberHandler creates a new ProberHandler instance
+func NewProberHandler(proberService *prober.ProberService) *ProberHandler {
+	return &ProberHandler{
+		proberService: proberService,
+	}
+}
+
+// GetStats returns the prober statistics: state, interval, cycle and
+// failure counters, queue depth, next probe times and cache statistics
+// GET /api/admin/prober
+func (h *ProberHandler) GetStats(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": h.proberService.GetStats(),
+	})
+}
+
+// ProbeAll queues every server for a probe right away; the probes run in
+// the background
+// POST /api/admin/prober/probe
+func (h *ProberHandler) ProbeAll(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	queued := h.proberService.ProbeAll()
+	message := "Servers queued for probing"
+	if h.proberService.IsPaused() {
+		message = "Servers queued for probing once the prober is resumed"
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"data":    gin.H{"queued": queued},
+		"message": message,
+	})
+}
+
+// ProbeServer probes a server right away and returns its new status. The
+// probe is abandoned if the client goes away.
+// POST /api/admin/prober/probe/:id
+func (h *ProberHandler) ProbeServer(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	serverID, ok := parseServerID(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.proberService.ForceProbeServer(c.Request.Context(), serverID)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Probe cancelled",
+			"message": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Server not found",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": status,
+	})
+}
+
+// Pause stops background probing; force probes still work
+// POST /api/admin/prober/pause
+func (h *ProberHandler) Pause(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.proberService.Pause()
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Prober paused",
+	})
+}
+
+// Resume continues background probing
+// POST /api/admin/prober/resume
+func (h *ProberHandler) Resume(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.proberService.Resume()
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Prober resumed",
+	})
+}
+
+// SetInterval changes the default probe interval until the next restart
+// PUT /api/admin/prober/interval
+func (h *ProberHandler) SetInterval(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var req models.ProbeIntervalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.proberService.SetProbeInterval(time.Duration(req.IntervalSeconds) * time.Second)
+	c.JSON(http.StatusOK, gin.H{
+		"data":    gin.H{"probe_interval": h.proberService.GetProbeInterval().String()},
+		"message": "Probe interval updated",
+	})
+}
+
+// GetCacheStats returns statistics of the status cache
+// GET /api/admin/prober/cache
+func (h *ProberHandler) GetCacheStats(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": h.proberService.GetCacheStats(),
+	})
+}
+
+// ClearCache drops all cached server statuses; servers show as offline
+// until they are probed again
+// DELETE /api/admin/prober/cache
+func (h *ProberHandler) ClearCache(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	h.proberService.ClearCache()
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Status cache cleared",
+	})
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/models"
+	"game-server-monitor/internal/prober"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProberHandler(t *testing.T) {
+	// Initialize test database
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+
+	// A TCP service to probe
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Failed to listen:", err)
+	}
+	defer listener.Close()
+
+	dbService := database.NewDatabaseService()
+	server, err := dbService.CreateServer(&models.CreateServerRequest{
+		Name:    "TCP Service",
+		Type:    "tcp",
+		Address: "127.0.0.1",
+		Port:    listener.Addr().(*net.TCPAddr).Port,
+	})
+	if err != nil {
+		t.Fatal("Failed to create server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	proberService := prober.NewProberService(dbService)
+	handler := NewProberHandler(proberService)
+
+	// Setup Gin router with an authenticated admin
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Set("username", "admin")
+	})
+	router.GET("/api/admin/prober", handler.GetStats)
+	router.POST("/api/admin/prober/probe", handler.ProbeAll)
+	router.POST("/api/admin/prober/probe/:id", handler.ProbeServer)
+	router.POST("/api/admin/prober/pause", handler.Pause)
+	router.POST("/api/admin/prober/resume", handler.Resume)
+	router.PUT("/api/admin/prober/interval", handler.SetInterval)
+	router.GET("/api/admin/prober/cache", handler.GetCacheStats)
+	router.DELETE("/api/admin/prober/cache", handler.ClearCache)
+
+	request := func(method, path, body string) (int, map[string]interface{}) {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+	data := func(response map[string]interface{}) map[string]interface{} {
+		data, _ := response["data"].(map[string]interface{})
+		return data
+	}
+
+	// Stats
+	code, response := request("GET", "/api/admin/prober", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, false, data(response)["paused"])
+	assert.Contains(t, data(response), "last_cycle_duration")
+	assert.Contains(t, data(response), "queued_servers")
+
+	// Pause and resume
+	code, _ = request("POST", "/api/admin/prober/pause", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, proberService.IsPaused())
+	code, _ = request("POST", "/api/admin/prober/resume", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, proberService.IsPaused())
+
+	// Interval
+	code, _ = request("PUT", "/api/admin/prober/interval", `{"interval_seconds": 2}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, response = request("PUT", "/api/admin/prober/interval", `{"interval_seconds": 60}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "1m0s", data(response)["probe_interval"])
+	assert.Equal(t, time.Minute, proberService.GetProbeInterval())
+
+	// Force probe of one server
+	code, response = request("POST", "/api/admin/prober/probe/"+strconv.Itoa(int(server.ID)), "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, data(response)["online"])
+	code, _ = request("POST", "/api/admin/prober/probe/abc", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = request("POST", "/api/admin/prober/probe/999999", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// Force probe of all servers only queues them
+	code, response = request("POST", "/api/admin/prober/probe", "")
+	assert.Equal(t, http.StatusAccepted, code)
+	assert.Contains(t, data(response), "queued")
+
+	// Cache
+	code, response = request("GET", "/api/admin/prober/cache", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(1), data(response)["total_cached"])
+	code, _ = request("DELETE", "/api/admin/prober/cache", "")
+	assert.Equal(t, http.StatusOK, code)
+	_, cached := proberService.GetServerStatus(server.ID)
+	assert.False(t, cached)
+}
+
+func TestProberHandler_Unauthorized(t *testing.T) {
+	handler := NewProberHandler(nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/admin/prober/pause", handler.Pause)
+
+	req, _ := http.NewRequest("POST", "/api/admin/prober/pause", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"game-server-monitor/internal/auth"
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/models"
+	"game-server-monitor/internal/protocol"
+	"game-server-monitor/internal/rcon"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	rconTimeout       = 10 * time.Second
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// RCONHandler handles RCON command execution for admins
+type RCONHandler struct {
+	dbService *database.DatabaseService
+	timeout   time.Duration
+}
+
+// NewRCONHandler creates a new RCONHandler instance
+func NewRCONHandler() *RCONHandler {
+	return &RCONHandler{
+		dbService: database.NewDatabaseService(),
+		timeout:   rconTimeout,
+	}
+}
+
+// Execute runs an allowlisted RCON command on a server and returns its output.
+// Every request, including denied ones, is recorded in the audit log.
+// POST /api/admin/servers/:id/rcon
+func (h *RCONHandler) Execute(c *gin.Context) {
+	// Verify admin is authenticated
+	userID, username, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	serverID, ok := parseServerID(c)
+	if !ok {
+		return
+	}
+
+	var req models.RCONRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	server, err := h.dbService.GetServer(serverID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Server not found",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	serverType, exists := protocol.Lookup(server.Type)
+	if !exists || !serverType.HasCapability(protocol.CapabilityRCON) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "RCON not supported",
+			"message": "Server type " + server.Type + " does not support RCON",
+		})
+		return
+	}
+	if server.RCONPassword == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "RCON not configured",
+			"message": "Set an RCON password for this server first",
+		})
+		return
+	}
+
+	entry := &models.RCONAuditEntry{
+		ServerID: server.ID,
+		UserID:   userID,
+		Username: username,
+		Command:  req.Command,
+	}
+
+	if !rcon.Allowed(server.RCONCommands, req.Command) {
+		entry.Status = models.RCONStatusDenied
+		entry.Error = "command not on the allowlist"
+		h.record(entry)
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Command not allowed",
+			"message": "The command is not on this server's RCON allowlist",
+		})
+		return
+	}
+
+	address := net.JoinHostPort(server.Address, strconv.Itoa(serverType.RCONPort(server)))
+	output, err := h.execute(c.Request.Context(), server, address, req.Command)
+	if err != nil {
+		entry.Status = models.RCONStatusFailed
+		entry.Error = err.Error()
+		h.record(entry)
+
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "RCON command failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	entry.Status = models.RCONStatusSuccess
+	entry.Output = output
+	h.record(entry)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"command": req.Command,
+			"output":  output,
+		},
+	})
+}
+
+// GetAuditLog returns the RCON audit log of a server
+// GET /api/admin/servers/:id/rcon/audit?limit=50
+func (h *RCONHandler) GetAuditLog(c *gin.Context) {
+	// Verify admin is authenticated
+	_, _, err := auth.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	serverID, ok := parseServerID(c)
+	if !ok {
+		return
+	}
+
+	limit := defaultAuditLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > maxAuditLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid limit",
+				"message": "Limit must be a number between 1 and 500",
+			})
+			return
+		}
+	}
+
+	entries, err := h.dbService.GetRCONAuditLog(serverID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve audit log",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": entries,
+	})
+}
+
+// execute connects to the server's RCON port and runs a command, giving up
+// when ctx is done
+func (h *RCONHandler) execute(ctx context.Context, server *models.Server, address, command string) (string, error) {
+	password, err := h.dbService.GetRCONPassword(server)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := rcon.DialContext(ctx, address, password, h.timeout)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	return client.Execute(command)
+}
+
+// record adds an entry to the audit log, logging failures
+func (h *RCONHandler) record(entry *models.RCONAuditEntry) {
+	if err := h.dbService.RecordRCONExecution(entry); err != nil {
+		log.Printf("Failed to record RCON audit entry for server %d: %v", entry.ServerID, err)
+	}
+}
+
+// parseServerID parses the server ID URL parameter, responding with 400 if invalid
+func parseServerID(c *gin.Context) (uint, bool) {
+	serverID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid server ID",
+			"message": "Server ID must be a valid number",
+		})
+		return 0, false
+	}
+	return uint(serverID), true
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// startFakeRCON runs a minimal RCON server that accepts the given password
+// and answers every command with "ran <command>", returning its port
+func startFakeRCON(t *testing.T, password string) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Failed to listen:", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				send := func(id, kind int32, body string) {
+					var buffer bytes.Buffer
+					binary.Write(&buffer, binary.LittleEndian, []int32{int32(10 + len(body)), id, kind})
+					buffer.WriteString(body + "\x00\x00")
+					conn.Write(buffer.Bytes())
+				}
+
+				for {
+					var header [3]int32
+					if err := binary.Read(reader, binary.LittleEndian, &header); err != nil {
+						return
+					}
+					body := make([]byte, header[0]-8)
+					if _, err := io.ReadFull(reader, body); err != nil {
+						return
+					}
+					command := string(bytes.TrimRight(body, "\x00"))
+
+					switch header[2] {
+					case 3: // Auth
+						if command != password {
+							send(-1, 2, "")
+						} else {
+							send(header[1], 2, "")
+						}
+					case 2: // Command
+						send(header[1], 0, "ran "+command)
+					default:
+						send(header[1], 0, "")
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestRCONHandler_Execute(t *testing.T) {
+	// Initialize test database
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+
+	dbService := database.NewDatabaseService()
+	server, err := dbService.CreateServer(&models.CreateServerRequest{
+		Name:         "RCON Server",
+		Type:         "minecraft",
+		Address:      "127.0.0.1",
+		Port:         25565,
+		RCONPort:     startFakeRCON(t, "secret"),
+		RCONPassword: "secret",
+		RCONCommands: []string{"list"},
+	})
+	if err != nil {
+		t.Fatal("Failed to create server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	// The password is only stored encrypted
+	assert.NotEqual(t, "secret", server.RCONPassword)
+	password, err := dbService.GetRCONPassword(server)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", password)
+
+	handler := NewRCONHandler()
+
+	// Setup Gin router with an authenticated admin
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Set("username", "admin")
+	})
+	router.POST("/api/admin/servers/:id/rcon", handler.Execute)
+	router.GET("/api/admin/servers/:id/rcon/audit", handler.GetAuditLog)
+
+	execute := func(command string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.RCONRequest{Command: command})
+		req, _ := http.NewRequest("POST", "/api/admin/servers/"+strconv.Itoa(int(server.ID))+"/rcon", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Allowlisted command
+	w := execute("list")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data struct {
+			Output string `json:"output"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "ran list", response.Data.Output)
+
+	// Command outside the allowlist
+	w = execute("stop")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Both requests are audited, newest first
+	req, _ := http.NewRequest("GET", "/api/admin/servers/"+strconv.Itoa(int(server.ID))+"/rcon/audit", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var audit struct {
+		Data []models.RCONAuditEntry `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &audit))
+	if assert.Len(t, audit.Data, 2) {
+		assert.Equal(t, "stop", audit.Data[0].Command)
+		assert.Equal(t, models.RCONStatusDenied, audit.Data[0].Status)
+		assert.Equal(t, "list", audit.Data[1].Command)
+		assert.Equal(t, models.RCONStatusSuccess, audit.Data[1].Status)
+		assert.Equal(t, "admin", audit.Data[1].Username)
+	}
+}
@@ -1,28 +1,36 @@
 package handlers
 
 import (
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"game-server-monitor/internal/auth"
 	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/history"
+	"game-server-monitor/internal/maintenance"
 	"game-server-monitor/internal/models"
 	"game-server-monitor/internal/prober"
+	"game-server-monitor/internal/protocol"
 
 	"github.com/gin-gonic/gin"
 )
 
 // ServerHandler handles server-related requests
 type ServerHandler struct {
-	dbService     *database.DatabaseService
-	proberService *prober.ProberService
+	dbService      *database.DatabaseService
+	proberService  *prober.ProberService
+	historyService *history.Service
 }
 
 // NewServerHandler creates a new ServerHandler instance
-func NewServerHandler(proberService *prober.ProberService) *ServerHandler {
+func NewServerHandler(proberService *prober.ProberService, historyService *history.Service) *ServerHandler {
 	return &ServerHandler{
-		dbService:     database.NewDatabaseService(),
-		proberService: proberService,
+		dbService:      database.NewDatabaseService(),
+		proberService:  proberService,
+		historyService: historyService,
 	}
 }
 
@@ -39,6 +47,14 @@ func (h *ServerHandler) GetServers(c *gin.Context) {
 		return
 	}
 
+	windows := h.maintenanceWindows()
+	icons := h.iconHashes()
+	now := time.Now()
+	for i := range serverListResponse.Servers {
+		attachMaintenance(&serverListResponse.Servers[i], windows, now)
+		attachIcon(&serverListResponse.Servers[i], icons)
+	}
+
 	// Return just the servers array, not the wrapper
 	c.JSON(http.StatusOK, gin.H{
 		"data": serverListResponse.Servers,
@@ -69,11 +85,115 @@ func (h *ServerHandler) GetServerByID(c *gin.Context) {
 		return
 	}
 
+	h.attachUptime(serverWithStatus)
+	attachMaintenance(serverWithStatus, h.maintenanceWindows(), time.Now())
+	attachIcon(serverWithStatus, h.iconHashes())
+
+	// Player names are only published on the detail page, unless hidden for privacy
+	if !serverWithStatus.HidePlayers {
+		serverWithStatus.PlayersList = serverWithStatus.Status.PlayerList
+	}
+	serverWithStatus.Rules = serverWithStatus.Status.Rules
+	serverWithStatus.Mods = serverWithStatus.Status.Mods
+
 	c.JSON(http.StatusOK, gin.H{
 		"data": serverWithStatus,
 	})
 }
 
+// GetServerIcon returns the last known icon of a server as PNG
+// GET /api/servers/:id/icon
+func (h *ServerHandler) GetServerIcon(c *gin.Context) {
+	serverIDStr := c.Param("id")
+	serverID, err := strconv.ParseUint(serverIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid server ID",
+			"message": "Server ID must be a valid number",
+		})
+		return
+	}
+
+	icon, err := h.dbService.GetServerIcon(uint(serverID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Icon not found",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	etag := `"` + icon.Hash + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=3600")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", icon.PNG)
+}
+
+// GetServerTypes returns the supported server types
+// GET /api/server-types
+func (h *ServerHandler) GetServerTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": protocol.Types(),
+	})
+}
+
+// attachUptime adds the uptime summary to a server response; it is only
+// computed for the server detail, as it queries four windows per server
+func (h *ServerHandler) attachUptime(response *models.ServerStatusResponse) {
+	summary, err := h.historyService.UptimeSummary(&response.Server)
+	if err != nil {
+		log.Printf("Failed to calculate uptime for server %s: %v", response.Name, err)
+		return
+	}
+	response.Uptime = summary
+}
+
+// maintenanceWindows loads the enabled maintenance windows, logging failures
+func (h *ServerHandler) maintenanceWindows() []models.MaintenanceWindow {
+	windows, err := h.dbService.GetEnabledMaintenanceWindows()
+	if err != nil {
+		log.Printf("Failed to load maintenance windows: %v", err)
+	}
+	return windows
+}
+
+// iconHashes loads the hashes of the stored server icons, logging failures
+func (h *ServerHandler) iconHashes() map[uint]string {
+	hashes, err := h.dbService.GetServerIconHashes()
+	if err != nil {
+		log.Printf("Failed to load server icons: %v", err)
+	}
+	return hashes
+}
+
+// attachIcon links the stored icon of a server; the hash in the URL changes
+// with the icon so clients can cache it
+func attachIcon(response *models.ServerStatusResponse, hashes map[uint]string) {
+	if hash, ok := hashes[response.ID]; ok && len(hash) >= 12 {
+		response.IconURL = fmt.Sprintf("/api/servers/%d/icon?v=%s", response.ID, hash[:12])
+	}
+}
+
+// attachMaintenance sets the server state and its current and upcoming maintenance
+func attachMaintenance(response *models.ServerStatusResponse, windows []models.MaintenanceWindow, now time.Time) {
+	response.Maintenance = maintenance.ActivePeriod(windows, response.ID, now)
+	response.NextMaintenance = maintenance.NextPeriod(windows, response.ID, now)
+
+	switch {
+	case response.Maintenance != nil:
+		response.State = models.StateMaintenance
+	case response.Status.Online:
+		response.State = models.StateOnline
+	default:
+		response.State = models.StateOffline
+	}
+}
+
 // Management endpoints (require authentication)
 
 // GetAdminServers returns all servers for admin management (without status)
@@ -99,8 +219,13 @@ func (h *ServerHandler) GetAdminServers(c *gin.Context) {
 		return
 	}
 
+	adminServers := make([]models.AdminServer, len(servers))
+	for i := range servers {
+		adminServers[i] = models.NewAdminServer(&servers[i])
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data": servers,
+		"data": adminServers,
 	})
 }
 
@@ -136,8 +261,11 @@ func (h *ServerHandler) CreateServer(c *gin.Context) {
 		return
 	}
 
+	// Probe the new server right away instead of waiting for its first turn
+	h.proberService.ServerCreated(server)
+
 	c.JSON(http.StatusCreated, gin.H{
-		"data":    server,
+		"data":    models.NewAdminServer(server),
 		"message": "Server created successfully",
 	})
 }
@@ -175,6 +303,9 @@ func (h *ServerHandler) UpdateServer(c *gin.Context) {
 		return
 	}
 
+	// Keep the previous configuration to tell whether the probe target changed
+	previous, _ := h.dbService.GetServer(uint(serverID))
+
 	// Update server using database service (includes validation)
 	server, err := h.dbService.UpdateServer(uint(serverID), &req)
 	if err != nil {
@@ -185,8 +316,10 @@ func (h *ServerHandler) UpdateServer(c *gin.Context) {
 		return
 	}
 
+	h.proberService.ServerUpdated(previous, server)
+
 	c.JSON(http.StatusOK, gin.H{
-		"data":    server,
+		"data":    models.NewAdminServer(server),
 		"message": "Server updated successfully",
 	})
 }
@@ -225,6 +358,9 @@ func (h *ServerHandler) DeleteServer(c *gin.Context) {
 		return
 	}
 
+	// Stop probing the server and drop its cached status
+	h.proberService.ServerDeleted(uint(serverID))
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Server deleted successfully",
 	})
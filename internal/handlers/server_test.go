@@ -5,11 +5,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 
 	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/history"
 	"game-server-monitor/internal/models"
 	"game-server-monitor/internal/prober"
+	"game-server-monitor/internal/protocol"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -24,7 +28,7 @@ func TestServerHandler_GetServers(t *testing.T) {
 	// Create test services
 	dbService := database.NewDatabaseService()
 	proberService := prober.NewProberService(dbService)
-	handler := NewServerHandler(proberService)
+	handler := NewServerHandler(proberService, history.NewService(dbService))
 
 	// Setup Gin router
 	gin.SetMode(gin.TestMode)
@@ -54,7 +58,7 @@ func TestServerHandler_GetServerByID_InvalidID(t *testing.T) {
 	// Create test services
 	dbService := database.NewDatabaseService()
 	proberService := prober.NewProberService(dbService)
-	handler := NewServerHandler(proberService)
+	handler := NewServerHandler(proberService, history.NewService(dbService))
 
 	// Setup Gin router
 	gin.SetMode(gin.TestMode)
@@ -83,7 +87,7 @@ func TestServerHandler_GetServerByID_NotFound(t *testing.T) {
 	// Create test services
 	dbService := database.NewDatabaseService()
 	proberService := prober.NewProberService(dbService)
-	handler := NewServerHandler(proberService)
+	handler := NewServerHandler(proberService, history.NewService(dbService))
 
 	// Setup Gin router
 	gin.SetMode(gin.TestMode)
@@ -112,7 +116,7 @@ func TestServerHandler_CreateServer_Unauthorized(t *testing.T) {
 	// Create test services
 	dbService := database.NewDatabaseService()
 	proberService := prober.NewProberService(dbService)
-	handler := NewServerHandler(proberService)
+	handler := NewServerHandler(proberService, history.NewService(dbService))
 
 	// Setup Gin router
 	gin.SetMode(gin.TestMode)
@@ -134,3 +138,259 @@ func TestServerHandler_CreateServer_Unauthorized(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "Unauthorized", response["error"])
 }
+
+func TestServerHandler_CreateServer_DefaultPort(t *testing.T) {
+	// Initialize test database
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+
+	// Create test services
+	dbService := database.NewDatabaseService()
+	proberService := prober.NewProberService(dbService)
+	handler := NewServerHandler(proberService, history.NewService(dbService))
+
+	// Setup Gin router with an authenticated admin
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Set("username", "admin")
+	})
+	router.POST("/api/admin/servers", handler.CreateServer)
+
+	// Without a port, the type's default port is used
+	validJSON := `{"name": "Web Map", "type": "http", "address": "localhost"}`
+	req, _ := http.NewRequest("POST", "/api/admin/servers", bytes.NewBufferString(validJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response struct {
+		Data models.Server `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 80, response.Data.Port)
+	defer dbService.DeleteServer(response.Data.ID)
+
+	// Types without a default port still require one
+	invalidJSON := `{"name": "Voice", "type": "tcp", "address": "localhost"}`
+	req, _ = http.NewRequest("POST", "/api/admin/servers", bytes.NewBufferString(invalidJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestServerHandler_GetServerTypes(t *testing.T) {
+	handler := &ServerHandler{}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/server-types", handler.GetServerTypes)
+
+	req, _ := http.NewRequest("GET", "/api/server-types", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data []protocol.ServerType `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	names := make([]string, 0, len(response.Data))
+	for _, serverType := range response.Data {
+		names = append(names, serverType.Name)
+		if serverType.Name == "minecraft" {
+			assert.Equal(t, 25565, serverType.DefaultPort)
+			assert.Equal(t, "Minecraft", serverType.DisplayName)
+		}
+	}
+	assert.Subset(t, names, []string{"minecraft", "minecraft_bedrock", "cs2"})
+}
+
+func TestServerHandler_GetServerIcon(t *testing.T) {
+	// Initialize test database
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+
+	// Create test services
+	dbService := database.NewDatabaseService()
+	proberService := prober.NewProberService(dbService)
+	handler := NewServerHandler(proberService, history.NewService(dbService))
+
+	png := []byte("\x89PNG\r\n\x1a\nicon data")
+	icon, err := dbService.SaveServerIcon(4242, png)
+	if err != nil {
+		t.Fatal("Failed to save icon:", err)
+	}
+	defer dbService.IconOps.Delete(4242)
+
+	// Setup Gin router
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/servers/:id/icon", handler.GetServerIcon)
+
+	req, _ := http.NewRequest("GET", "/api/servers/4242/icon", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, png, w.Body.Bytes())
+	assert.Equal(t, `"`+icon.Hash+`"`, w.Header().Get("ETag"))
+
+	// Revalidation with the current ETag
+	req, _ = http.NewRequest("GET", "/api/servers/4242/icon", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	// Server without icon
+	req, _ = http.NewRequest("GET", "/api/servers/4243/icon", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestServerHandler_RCONSettingsAdminOnly(t *testing.T) {
+	// Initialize test database
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+
+	dbService := database.NewDatabaseService()
+	server, err := dbService.CreateServer(&models.CreateServerRequest{
+		Name:         "RCON Server",
+		Type:         "minecraft",
+		Address:      "127.0.0.1",
+		Port:         25565,
+		RCONPort:     25575,
+		RCONPassword: "secret",
+		RCONCommands: []string{"list", "say"},
+	})
+	if err != nil {
+		t.Fatal("Failed to create server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	proberService := prober.NewProberService(dbService)
+	handler := NewServerHandler(proberService, history.NewService(dbService))
+
+	// Setup Gin router; only the admin endpoint has an authenticated admin
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/servers", handler.GetServers)
+	router.GET("/api/servers/:id", handler.GetServerByID)
+	admin := router.Group("/api/admin", func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		c.Set("username", "admin")
+	})
+	admin.GET("/servers", handler.GetAdminServers)
+
+	get := func(path string) map[string]interface{} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), path)
+		return response
+	}
+	hasRCONKeys := func(server map[string]interface{}) bool {
+		for key := range server {
+			if strings.HasPrefix(key, "rcon_") {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Public list and detail responses
+	for _, item := range get("/api/servers")["data"].([]interface{}) {
+		assert.False(t, hasRCONKeys(item.(map[string]interface{})), "RCON settings in server list")
+	}
+	detail := get("/api/servers/" + strconv.Itoa(int(server.ID)))["data"].(map[string]interface{})
+	assert.False(t, hasRCONKeys(detail), "RCON settings in server detail")
+
+	// Admin list
+	var found bool
+	for _, item := range get("/api/admin/servers")["data"].([]interface{}) {
+		adminServer := item.(map[string]interface{})
+		if uint(adminServer["id"].(float64)) != server.ID {
+			continue
+		}
+		found = true
+		assert.Equal(t, float64(25575), adminServer["rcon_port"])
+		assert.Equal(t, []interface{}{"list", "say"}, adminServer["rcon_commands"])
+		assert.NotContains(t, adminServer, "rcon_password")
+	}
+	assert.True(t, found, "Server missing from admin list")
+}
+
+func TestServerHandler_UptimeDetailOnly(t *testing.T) {
+	// Initialize test database
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+
+	dbService := database.NewDatabaseService()
+	server, err := dbService.CreateServer(&models.CreateServerRequest{
+		Name:    "Uptime Server",
+		Type:    "tcp",
+		Address: "127.0.0.1",
+		Port:    25565,
+	})
+	if err != nil {
+		t.Fatal("Failed to create server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	proberService := prober.NewProberService(dbService)
+	handler := NewServerHandler(proberService, history.NewService(dbService))
+
+	// Setup Gin router
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/servers", handler.GetServers)
+	router.GET("/api/servers/:id", handler.GetServerByID)
+
+	// The list skips the uptime summary
+	req, _ := http.NewRequest("GET", "/api/servers", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var list struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.NotEmpty(t, list.Data)
+	for _, item := range list.Data {
+		assert.NotContains(t, item, "uptime")
+	}
+
+	// The detail includes it
+	req, _ = http.NewRequest("GET", "/api/servers/"+strconv.Itoa(int(server.ID)), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var detail struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+	assert.Contains(t, detail.Data, "uptime")
+}
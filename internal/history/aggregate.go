@@ -0,0 +1,200 @@
+package history
+
+import (
+	"game-server-monitor/internal/models"
+	"sort"
+	"time"
+)
+
+// sampleRollup converts a single probe result into a one-probe rollup
+func sampleRollup(sample models.StatusSample) models.StatusRollup {
+	rollup := models.StatusRollup{
+		ServerID:    sample.ServerID,
+		BucketStart: sample.Timestamp,
+		Samples:     1,
+		MinPlayers:  sample.Players,
+		MaxPlayers:  sample.Players,
+		AvgPlayers:  float64(sample.Players),
+		Capacity:    sample.MaxPlayers,
+	}
+
+	if sample.Online {
+		rollup.OnlineSamples = 1
+		rollup.AvgPing = float64(sample.Ping)
+		rollup.P95Ping = float64(sample.Ping)
+		rollup.Uptime = 1
+		rollup.Version = sample.Version
+	}
+
+	// Probes during maintenance do not count towards uptime
+	if sample.Maintenance {
+		rollup.MaintenanceSamples = 1
+		rollup.Uptime = 0
+	}
+
+	return rollup
+}
+
+// sampleRollups converts probe results into one-probe rollups
+func sampleRollups(samples []models.StatusSample) []models.StatusRollup {
+	rollups := make([]models.StatusRollup, 0, len(samples))
+	for _, sample := range samples {
+		rollups = append(rollups, sampleRollup(sample))
+	}
+	return rollups
+}
+
+// mergeRollups combines chronologically ordered rollups into one aggregate.
+// Averages are weighted by probe count, uptime by probes outside maintenance.
+// The 95th percentile ping is exact for one-probe inputs and approximated
+// from the inputs' percentiles otherwise.
+func mergeRollups(parts []models.StatusRollup) models.StatusRollup {
+	var merged models.StatusRollup
+	var playerSum, pingSum, uptimeSum float64
+	pings := make([]weightedValue, 0, len(parts))
+
+	for _, part := range parts {
+		if part.Samples == 0 {
+			continue
+		}
+
+		if merged.Samples == 0 || part.MinPlayers < merged.MinPlayers {
+			merged.MinPlayers = part.MinPlayers
+		}
+		if part.MaxPlayers > merged.MaxPlayers {
+			merged.MaxPlayers = part.MaxPlayers
+		}
+		if part.Capacity > merged.Capacity {
+			merged.Capacity = part.Capacity
+		}
+		if part.Version != "" {
+			merged.Version = part.Version
+		}
+
+		merged.Samples += part.Samples
+		merged.OnlineSamples += part.OnlineSamples
+		merged.MaintenanceSamples += part.MaintenanceSamples
+		playerSum += part.AvgPlayers * float64(part.Samples)
+		uptimeSum += part.Uptime * float64(part.Samples-part.MaintenanceSamples)
+
+		if part.OnlineSamples > 0 {
+			pingSum += part.AvgPing * float64(part.OnlineSamples)
+			pings = append(pings, weightedValue{value: part.P95Ping, weight: part.OnlineSamples})
+		}
+	}
+
+	if merged.Samples > 0 {
+		merged.AvgPlayers = playerSum / float64(merged.Samples)
+	}
+	if monitored := merged.Samples - merged.MaintenanceSamples; monitored > 0 {
+		merged.Uptime = uptimeSum / float64(monitored)
+	}
+	if merged.OnlineSamples > 0 {
+		merged.AvgPing = pingSum / float64(merged.OnlineSamples)
+		merged.P95Ping = weightedPercentile(pings, 0.95)
+	}
+
+	return merged
+}
+
+// weightedValue is a value counted weight times in a percentile
+type weightedValue struct {
+	value  float64
+	weight int
+}
+
+// weightedPercentile returns the nearest-rank percentile of weighted values
+func weightedPercentile(values []weightedValue, percentile float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i].value < values[j].value })
+
+	total := 0
+	for _, v := range values {
+		total += v.weight
+	}
+
+	rank := percentile * float64(total)
+	cumulative := 0
+	for _, v := range values {
+		cumulative += v.weight
+		if float64(cumulative) >= rank {
+			return v.value
+		}
+	}
+
+	return values[len(values)-1].value
+}
+
+// groupRollups merges chronologically ordered rollups into buckets of the
+// given width aligned to UTC, keyed by bucket start
+func groupRollups(parts []models.StatusRollup, width time.Duration) []models.StatusRollup {
+	var grouped []models.StatusRollup
+	var current []models.StatusRollup
+	var currentStart time.Time
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		merged := mergeRollups(current)
+		merged.ServerID = current[0].ServerID
+		merged.BucketStart = currentStart
+		grouped = append(grouped, merged)
+		current = current[:0]
+	}
+
+	for _, part := range parts {
+		start := part.BucketStart.UTC().Truncate(width)
+		if len(current) > 0 && !start.Equal(currentStart) {
+			flush()
+		}
+		currentStart = start
+		current = append(current, part)
+	}
+	flush()
+
+	return grouped
+}
+
+// bucketRollups aggregates rollups into fixed-width buckets starting at from.
+// Rollups starting before from are counted in the first bucket. Buckets
+// without data are kept so that gaps remain visible in the series.
+func bucketRollups(parts []models.StatusRollup, from, to time.Time, step time.Duration) []models.HistoryPoint {
+	count := int((to.Sub(from) + step - 1) / step)
+	buckets := make([][]models.StatusRollup, count)
+
+	for _, part := range parts {
+		i := 0
+		if part.BucketStart.After(from) {
+			i = int(part.BucketStart.Sub(from) / step)
+		}
+		if i >= count {
+			continue
+		}
+		buckets[i] = append(buckets[i], part)
+	}
+
+	points := make([]models.HistoryPoint, count)
+	for i := range points {
+		merged := mergeRollups(buckets[i])
+		points[i] = models.HistoryPoint{
+			Timestamp:  from.Add(time.Duration(i) * step),
+			Samples:    merged.Samples,
+			Uptime:     merged.Uptime,
+			MinPlayers: merged.MinPlayers,
+			MaxPlayers: merged.MaxPlayers,
+			AvgPlayers: merged.AvgPlayers,
+			Capacity:   merged.Capacity,
+			AvgPing:    merged.AvgPing,
+			P95Ping:    merged.P95Ping,
+			Version:    merged.Version,
+
+			MaintenanceSamples: merged.MaintenanceSamples,
+		}
+	}
+
+	return points
+}
@@ -0,0 +1,180 @@
+package history
+
+import (
+	"fmt"
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/models"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRange is the query range used when no start time is given
+	DefaultRange = 24 * time.Hour
+	// DefaultPoints is the number of buckets targeted when no step is given
+	DefaultPoints = 120
+	// MaxPoints limits the number of buckets a single query may return
+	MaxPoints = 1000
+	// MinStep is the smallest bucket width accepted
+	MinStep = 10 * time.Second
+)
+
+// QueryError reports a history or uptime query with an invalid time range
+// or step, as opposed to a failure reading the stored data
+type QueryError struct {
+	Message string
+}
+
+func (e *QueryError) Error() string {
+	return e.Message
+}
+
+// Service answers status history queries, transparently reading from the
+// storage tier that best fits the requested range and step
+type Service struct {
+	dbService *database.DatabaseService
+	policy    *RetentionPolicy
+	summaries map[uint]cachedSummary
+	mutex     sync.Mutex
+}
+
+// NewService creates a new history Service using the configured retention policy
+func NewService(dbService *database.DatabaseService) *Service {
+	return NewServiceWithPolicy(dbService, LoadRetentionPolicy())
+}
+
+// NewServiceWithPolicy creates a new history Service with a custom retention policy
+func NewServiceWithPolicy(dbService *database.DatabaseService, policy *RetentionPolicy) *Service {
+	if policy == nil {
+		policy = DefaultRetentionPolicy()
+	}
+
+	return &Service{
+		dbService: dbService,
+		policy:    policy,
+		summaries: make(map[uint]cachedSummary),
+	}
+}
+
+// GetHistory returns the bucketed status series of a server in [from, to).
+// A zero step selects one that yields roughly DefaultPoints buckets. The step
+// is widened to the tier resolution when finer data has already expired.
+func (s *Service) GetHistory(serverID uint, from, to time.Time, step time.Duration) (*models.HistoryResponse, error) {
+	step, err := ResolveStep(from, to, step)
+	if err != nil {
+		return nil, err
+	}
+
+	level := s.policy.selectTier(from, step, time.Now())
+	if resolution := tiers[level].resolution; resolution > step {
+		step = resolution
+	}
+
+	chunks, err := s.loadRange(serverID, level, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.HistoryResponse{
+		ServerID:   serverID,
+		From:       from,
+		To:         to,
+		Step:       int64(step / time.Second),
+		Resolution: tiers[level].name,
+		Points:     bucketRollups(flattenChunks(chunks), from, to, step),
+	}, nil
+}
+
+// chunk holds chronologically ordered data read from a single tier
+type chunk struct {
+	level int
+	parts []models.StatusRollup
+}
+
+// flattenChunks concatenates the parts of chronologically ordered chunks
+func flattenChunks(chunks []chunk) []models.StatusRollup {
+	var parts []models.StatusRollup
+	for _, c := range chunks {
+		parts = append(parts, c.parts...)
+	}
+	return parts
+}
+
+// loadRange reads [from, to) from the given tier. The tail that has not been
+// rolled up yet is filled from the next finer tier, so the returned chunks
+// are ordered from coarsest (oldest) to finest (newest).
+func (s *Service) loadRange(serverID uint, level int, from, to time.Time) ([]chunk, error) {
+	if level == 0 {
+		samples, err := s.dbService.GetStatusSamples(serverID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		return []chunk{{level: 0, parts: sampleRollups(samples)}}, nil
+	}
+
+	t := tiers[level]
+	parts, err := s.dbService.GetStatusRollups(serverID, t.name, from.UTC().Truncate(t.resolution), to)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := []chunk{{level: level, parts: parts}}
+
+	covered := from
+	if len(parts) > 0 {
+		if end := parts[len(parts)-1].BucketStart.Add(t.resolution); end.After(covered) {
+			covered = end
+		}
+	}
+
+	if covered.Before(to) {
+		tail, err := s.loadRange(serverID, level-1, covered, to)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, tail...)
+	}
+
+	return chunks, nil
+}
+
+// ResolveStep validates a query range and picks a step when none is given
+func ResolveStep(from, to time.Time, step time.Duration) (time.Duration, error) {
+	if !from.Before(to) {
+		return 0, &QueryError{Message: "invalid time range: from must be before to"}
+	}
+
+	span := to.Sub(from)
+
+	if step == 0 {
+		step = (span / DefaultPoints).Round(time.Second)
+		if step < MinStep {
+			step = MinStep
+		}
+	}
+
+	if step < MinStep {
+		return 0, &QueryError{Message: fmt.Sprintf("invalid step: must be at least %v", MinStep)}
+	}
+
+	if span/step >= MaxPoints {
+		return 0, &QueryError{Message: fmt.Sprintf("too many data points: range / step must be below %d", MaxPoints)}
+	}
+
+	return step, nil
+}
+
+// BucketSamples aggregates raw samples in [from, to) into fixed-width buckets
+// starting at from. Buckets without samples are kept so that gaps remain
+// visible in the series.
+func BucketSamples(samples []models.StatusSample, from, to time.Time, step time.Duration) []models.HistoryPoint {
+	inRange := make([]models.StatusSample, 0, len(samples))
+	for _, sample := range samples {
+		if sample.Timestamp.Before(from) || !sample.Timestamp.Before(to) {
+			continue
+		}
+		inRange = append(inRange, sample)
+	}
+
+	return bucketRollups(sampleRollups(inRange), from, to, step)
+}
@@ -0,0 +1,151 @@
+package history
+
+import (
+	"game-server-monitor/internal/models"
+	"testing"
+	"time"
+)
+
+func TestBucketSamples(t *testing.T) {
+	from := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Minute)
+
+	samples := []models.StatusSample{
+		{Timestamp: from.Add(10 * time.Second), Online: true, Players: 4, MaxPlayers: 20, Ping: 40, Version: "1.20.1"},
+		{Timestamp: from.Add(40 * time.Second), Online: true, Players: 8, MaxPlayers: 20, Ping: 60, Version: "1.20.2"},
+		{Timestamp: from.Add(70 * time.Second), Online: false},
+		{Timestamp: from.Add(100 * time.Second), Online: true, Players: 2, MaxPlayers: 20, Ping: 50},
+		{Timestamp: to, Online: true, Players: 99}, // Outside of range
+	}
+
+	points := BucketSamples(samples, from, to, time.Minute)
+
+	if len(points) != 3 {
+		t.Fatalf("Expected 3 buckets, got %d", len(points))
+	}
+
+	first := points[0]
+	if first.Samples != 2 || first.Uptime != 1 {
+		t.Errorf("Expected 2 online samples in first bucket, got samples=%d uptime=%v", first.Samples, first.Uptime)
+	}
+	if first.MinPlayers != 4 || first.MaxPlayers != 8 || first.AvgPlayers != 6 {
+		t.Errorf("Unexpected player stats in first bucket: %+v", first)
+	}
+	if first.AvgPing != 50 {
+		t.Errorf("Expected average ping 50, got %v", first.AvgPing)
+	}
+	if first.Version != "1.20.2" {
+		t.Errorf("Expected last seen version 1.20.2, got %s", first.Version)
+	}
+
+	second := points[1]
+	if second.Samples != 2 || second.Uptime != 0.5 {
+		t.Errorf("Expected half uptime in second bucket, got samples=%d uptime=%v", second.Samples, second.Uptime)
+	}
+	if second.AvgPing != 50 {
+		t.Errorf("Expected offline samples to be excluded from ping, got %v", second.AvgPing)
+	}
+
+	if points[2].Samples != 0 {
+		t.Errorf("Expected empty third bucket, got %d samples", points[2].Samples)
+	}
+	if !points[2].Timestamp.Equal(from.Add(2 * time.Minute)) {
+		t.Errorf("Unexpected third bucket start: %v", points[2].Timestamp)
+	}
+}
+
+func TestResolveStep(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	step, err := ResolveStep(from, to, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if step != 12*time.Minute {
+		t.Errorf("Expected automatic step of 12m, got %v", step)
+	}
+
+	if _, err := ResolveStep(from, to, time.Second); err == nil {
+		t.Error("Expected error for step below minimum")
+	}
+
+	if _, err := ResolveStep(from, to, MinStep); err == nil {
+		t.Error("Expected error for too many data points")
+	}
+
+	if _, err := ResolveStep(to, from, time.Minute); err == nil {
+		t.Error("Expected error for inverted range")
+	}
+}
+
+func TestSelectTier(t *testing.T) {
+	policy := DefaultRetentionPolicy()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		step     time.Duration
+		expected string
+	}{
+		{"recent range with fine step", now.Add(-time.Hour), 30 * time.Second, ResolutionRaw},
+		{"day with 12 minute step", now.Add(-24 * time.Hour), 12 * time.Minute, models.RollupTier5m},
+		{"week with hourly step", now.Add(-7 * 24 * time.Hour), time.Hour, models.RollupTier1h},
+		{"raw expired with fine step", now.Add(-7 * 24 * time.Hour), time.Minute, models.RollupTier5m},
+		{"year with daily step", now.Add(-365 * 24 * time.Hour), 24 * time.Hour, models.RollupTier1d},
+		{"beyond all retention", now.Add(-1000 * 24 * time.Hour), time.Minute, models.RollupTier1d},
+	}
+
+	for _, tt := range tests {
+		level := policy.selectTier(tt.from, tt.step, now)
+		if tiers[level].name != tt.expected {
+			t.Errorf("%s: expected tier %s, got %s", tt.name, tt.expected, tiers[level].name)
+		}
+	}
+}
+
+func TestMergeRollups(t *testing.T) {
+	samples := make([]models.StatusSample, 0, 20)
+	for i := 1; i <= 20; i++ {
+		samples = append(samples, models.StatusSample{Online: true, Players: i, MaxPlayers: 32, Ping: int64(i * 10)})
+	}
+	samples = append(samples, models.StatusSample{Online: false})
+
+	merged := mergeRollups(sampleRollups(samples))
+
+	if merged.Samples != 21 || merged.OnlineSamples != 20 {
+		t.Errorf("Unexpected sample counts: %d/%d", merged.OnlineSamples, merged.Samples)
+	}
+	if merged.P95Ping != 190 {
+		t.Errorf("Expected p95 ping of 190, got %v", merged.P95Ping)
+	}
+	if merged.AvgPing != 105 {
+		t.Errorf("Expected average ping of 105, got %v", merged.AvgPing)
+	}
+	if merged.MinPlayers != 0 || merged.MaxPlayers != 20 || merged.Capacity != 32 {
+		t.Errorf("Unexpected player stats: %+v", merged)
+	}
+
+	// Merging already merged halves keeps weighted averages intact
+	halves := []models.StatusRollup{
+		mergeRollups(sampleRollups(samples[:10])),
+		mergeRollups(sampleRollups(samples[10:])),
+	}
+	remerged := mergeRollups(halves)
+	if remerged.AvgPing != merged.AvgPing || remerged.AvgPlayers != merged.AvgPlayers || remerged.Uptime != merged.Uptime {
+		t.Errorf("Expected re-merged averages to match, got %+v vs %+v", remerged, merged)
+	}
+}
+
+func TestParseRetention(t *testing.T) {
+	if d, err := ParseRetention("14d"); err != nil || d != 14*24*time.Hour {
+		t.Errorf("Expected 14 days, got %v (%v)", d, err)
+	}
+	if d, err := ParseRetention("36h"); err != nil || d != 36*time.Hour {
+		t.Errorf("Expected 36 hours, got %v (%v)", d, err)
+	}
+	if _, err := ParseRetention("soon"); err == nil {
+		t.Error("Expected error for invalid retention")
+	}
+}
@@ -0,0 +1,145 @@
+package history
+
+import (
+	"fmt"
+	"game-server-monitor/internal/models"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResolutionRaw names the unaggregated probe results
+const ResolutionRaw = "raw"
+
+// tier describes one level of history storage
+type tier struct {
+	name       string
+	resolution time.Duration // Bucket width (0 for raw samples)
+}
+
+// tiers lists the storage levels from finest to coarsest
+var tiers = []tier{
+	{name: ResolutionRaw, resolution: 0},
+	{name: models.RollupTier5m, resolution: 5 * time.Minute},
+	{name: models.RollupTier1h, resolution: time.Hour},
+	{name: models.RollupTier1d, resolution: 24 * time.Hour},
+}
+
+// RetentionPolicy controls how long each history tier is kept.
+// A zero retention keeps the tier forever.
+type RetentionPolicy struct {
+	Raw            time.Duration
+	FiveMinute     time.Duration
+	Hourly         time.Duration
+	Daily          time.Duration
+	RollupInterval time.Duration // How often the rollup job runs
+}
+
+// DefaultRetentionPolicy returns the default retention policy
+func DefaultRetentionPolicy() *RetentionPolicy {
+	return &RetentionPolicy{
+		Raw:            48 * time.Hour,       // Keep raw probes for 2 days
+		FiveMinute:     14 * 24 * time.Hour,  // Keep 5-minute rollups for 2 weeks
+		Hourly:         90 * 24 * time.Hour,  // Keep hourly rollups for 90 days
+		Daily:          730 * 24 * time.Hour, // Keep daily rollups for 2 years
+		RollupInterval: 5 * time.Minute,
+	}
+}
+
+// LoadRetentionPolicy returns the default retention policy with overrides
+// from the HISTORY_RETENTION_RAW, HISTORY_RETENTION_5M, HISTORY_RETENTION_1H
+// and HISTORY_RETENTION_1D environment variables applied
+func LoadRetentionPolicy() *RetentionPolicy {
+	policy := DefaultRetentionPolicy()
+
+	overrides := map[string]*time.Duration{
+		"HISTORY_RETENTION_RAW": &policy.Raw,
+		"HISTORY_RETENTION_5M":  &policy.FiveMinute,
+		"HISTORY_RETENTION_1H":  &policy.Hourly,
+		"HISTORY_RETENTION_1D":  &policy.Daily,
+	}
+
+	for name, target := range overrides {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		retention, err := ParseRetention(value)
+		if err != nil {
+			log.Printf("Warning: Ignoring %s: %v", name, err)
+			continue
+		}
+		*target = retention
+	}
+
+	return policy
+}
+
+// ParseRetention parses a retention period given as a Go duration ("36h")
+// or a number of days ("14d")
+func ParseRetention(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid retention %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	retention, err := time.ParseDuration(value)
+	if err != nil || retention < 0 {
+		return 0, fmt.Errorf("invalid retention %q", value)
+	}
+	return retention, nil
+}
+
+// retention returns the retention period of a tier
+func (p *RetentionPolicy) retention(name string) time.Duration {
+	switch name {
+	case ResolutionRaw:
+		return p.Raw
+	case models.RollupTier5m:
+		return p.FiveMinute
+	case models.RollupTier1h:
+		return p.Hourly
+	case models.RollupTier1d:
+		return p.Daily
+	}
+	return 0
+}
+
+// covers reports whether a tier still holds data starting at from
+func (p *RetentionPolicy) covers(name string, from, now time.Time) bool {
+	retention := p.retention(name)
+	return retention == 0 || !from.Before(now.Add(-retention))
+}
+
+// selectTier picks the coarsest tier that still holds data at from and whose
+// resolution fits within step. If no such tier exists the finest tier holding
+// data at from is used instead, falling back to the coarsest tier overall.
+func (p *RetentionPolicy) selectTier(from time.Time, step time.Duration, now time.Time) int {
+	selected := -1
+
+	for i, t := range tiers {
+		if !p.covers(t.name, from, now) {
+			continue
+		}
+		if t.resolution <= step {
+			selected = i
+			continue
+		}
+		if selected == -1 {
+			selected = i
+		}
+		break
+	}
+
+	if selected == -1 {
+		selected = len(tiers) - 1
+	}
+
+	return selected
+}
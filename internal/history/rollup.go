@@ -0,0 +1,201 @@
+package history
+
+import (
+	"context"
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/models"
+	"log"
+	"sync"
+	"time"
+)
+
+// Rollup periodically compacts raw probe results into 5-minute, hourly and
+// daily aggregates and removes data that exceeded its tier's retention
+type Rollup struct {
+	dbService *database.DatabaseService
+	policy    *RetentionPolicy
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	running   bool
+	mutex     sync.RWMutex
+}
+
+// NewRollup creates a new rollup job; a nil policy loads the configured one
+func NewRollup(dbService *database.DatabaseService, policy *RetentionPolicy) *Rollup {
+	if policy == nil {
+		policy = LoadRetentionPolicy()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Rollup{
+		dbService: dbService,
+		policy:    policy,
+		ctx:       ctx,
+		cancel:    cancel,
+		running:   false,
+	}
+}
+
+// Start begins the background rollup process
+func (r *Rollup) Start() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.running {
+		return nil // Already running
+	}
+
+	r.running = true
+	r.wg.Add(1)
+
+	go r.rollupLoop()
+
+	log.Printf("History rollup started with interval: %v", r.policy.RollupInterval)
+	return nil
+}
+
+// Stop gracefully stops the background rollup process
+func (r *Rollup) Stop() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.running {
+		return nil // Already stopped
+	}
+
+	r.running = false
+	r.cancel()
+	r.wg.Wait()
+
+	log.Println("History rollup stopped")
+	return nil
+}
+
+// IsRunning returns whether the rollup job is currently running
+func (r *Rollup) IsRunning() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.running
+}
+
+// rollupLoop is the main background rollup loop
+func (r *Rollup) rollupLoop() {
+	defer r.wg.Done()
+
+	// Catch up on startup
+	r.RunOnce(time.Now())
+
+	ticker := time.NewTicker(r.policy.RollupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.RunOnce(time.Now())
+		}
+	}
+}
+
+// RunOnce compacts all complete buckets up to now and prunes expired data
+func (r *Rollup) RunOnce(now time.Time) {
+	servers, err := r.dbService.GetAllServers()
+	if err != nil {
+		log.Printf("Failed to get servers for history rollup: %v", err)
+		return
+	}
+
+	// Each tier is built from the one below it, so roll up finest first
+	for level := 1; level < len(tiers); level++ {
+		for _, server := range servers {
+			if err := r.rollupServer(server.ID, level, now); err != nil {
+				log.Printf("Failed to roll up %s history for server %s: %v", tiers[level].name, server.Name, err)
+			}
+		}
+	}
+
+	r.prune(now)
+}
+
+// rollupServer (re)builds the tier's buckets of a server from the last stored
+// bucket up to the last complete one
+func (r *Rollup) rollupServer(serverID uint, level int, now time.Time) error {
+	t := tiers[level]
+	source := tiers[level-1]
+	ops := r.dbService.HistoryOps
+
+	end := now.UTC().Truncate(t.resolution)
+
+	start, found, err := ops.LatestRollupStart(serverID, t.name)
+	if err != nil {
+		return err
+	}
+
+	// Nothing rolled up yet, start from the oldest source data
+	if !found {
+		if level == 1 {
+			start, found, err = ops.EarliestSampleTime(serverID)
+		} else {
+			start, found, err = ops.EarliestRollupStart(serverID, source.name)
+		}
+		if err != nil || !found {
+			return err
+		}
+		start = start.UTC().Truncate(t.resolution)
+	}
+
+	if !start.Before(end) {
+		return nil
+	}
+
+	var parts []models.StatusRollup
+	if level == 1 {
+		samples, err := ops.GetSamples(serverID, start, end)
+		if err != nil {
+			return err
+		}
+		parts = sampleRollups(samples)
+	} else {
+		parts, err = ops.GetRollups(serverID, source.name, start, end)
+		if err != nil {
+			return err
+		}
+	}
+
+	rollups := groupRollups(parts, t.resolution)
+	for i := range rollups {
+		rollups[i].ServerID = serverID
+		rollups[i].Tier = t.name
+	}
+
+	return ops.UpsertRollups(rollups)
+}
+
+// prune removes data older than each tier's retention
+func (r *Rollup) prune(now time.Time) {
+	for _, t := range tiers {
+		retention := r.policy.retention(t.name)
+		if retention == 0 {
+			continue
+		}
+
+		before := now.Add(-retention)
+
+		var deleted int64
+		var err error
+		if t.name == ResolutionRaw {
+			deleted, err = r.dbService.HistoryOps.DeleteSamplesBefore(before)
+		} else {
+			deleted, err = r.dbService.HistoryOps.DeleteRollupsBefore(t.name, before)
+		}
+
+		if err != nil {
+			log.Printf("Failed to prune %s history: %v", t.name, err)
+		} else if deleted > 0 {
+			log.Printf("Pruned %d expired %s history entries", deleted, t.name)
+		}
+	}
+}
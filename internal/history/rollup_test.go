@@ -0,0 +1,85 @@
+package history
+
+import (
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/models"
+	"testing"
+	"time"
+)
+
+func TestRollup_RunOnce(t *testing.T) {
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+
+	dbService := database.NewDatabaseService()
+	server, err := dbService.CreateServer(&models.CreateServerRequest{
+		Name:    "Rollup Test",
+		Type:    "minecraft",
+		Address: "localhost",
+		Port:    25565,
+	})
+	if err != nil {
+		t.Fatal("Failed to create test server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	now := time.Now().UTC().Truncate(time.Hour)
+	start := now.Add(-2 * time.Hour)
+
+	// One probe per minute for two hours, offline during the last 30 minutes
+	for ts := start; ts.Before(now); ts = ts.Add(time.Minute) {
+		status := &models.ServerStatus{Online: true, Players: 10, MaxPlayers: 20, Ping: 50, LastUpdated: ts}
+		if ts.After(now.Add(-30 * time.Minute)) {
+			status = &models.ServerStatus{Online: false, LastUpdated: ts}
+		}
+		if err := dbService.RecordStatusSample(server.ID, status); err != nil {
+			t.Fatal("Failed to record sample:", err)
+		}
+	}
+
+	policy := DefaultRetentionPolicy()
+	NewRollup(dbService, policy).RunOnce(now)
+
+	fiveMinute, err := dbService.GetStatusRollups(server.ID, models.RollupTier5m, start, now)
+	if err != nil {
+		t.Fatal("Failed to read 5m rollups:", err)
+	}
+	if len(fiveMinute) != 24 {
+		t.Fatalf("Expected 24 five-minute rollups, got %d", len(fiveMinute))
+	}
+	if fiveMinute[0].Samples != 5 || fiveMinute[0].Uptime != 1 {
+		t.Errorf("Unexpected first rollup: %+v", fiveMinute[0])
+	}
+
+	hourly, err := dbService.GetStatusRollups(server.ID, models.RollupTier1h, start, now)
+	if err != nil {
+		t.Fatal("Failed to read hourly rollups:", err)
+	}
+	if len(hourly) != 2 {
+		t.Fatalf("Expected 2 hourly rollups, got %d", len(hourly))
+	}
+	if hourly[1].Samples != 60 || hourly[1].OnlineSamples != 31 {
+		t.Errorf("Unexpected second hourly rollup: %+v", hourly[1])
+	}
+
+	// Running again must not duplicate buckets
+	NewRollup(dbService, policy).RunOnce(now)
+	hourly, _ = dbService.GetStatusRollups(server.ID, models.RollupTier1h, start, now)
+	if len(hourly) != 2 {
+		t.Errorf("Expected rollups to be idempotent, got %d hourly rollups", len(hourly))
+	}
+
+	// An hourly step is served from the hourly tier
+	service := NewServiceWithPolicy(dbService, policy)
+	result, err := service.GetHistory(server.ID, start, now, time.Hour)
+	if err != nil {
+		t.Fatal("Failed to query history:", err)
+	}
+	if result.Resolution != models.RollupTier1h {
+		t.Errorf("Expected hourly resolution, got %s", result.Resolution)
+	}
+	if len(result.Points) != 2 || result.Points[0].Samples != 60 {
+		t.Errorf("Unexpected history points: %+v", result.Points)
+	}
+}
@@ -0,0 +1,212 @@
+package history
+
+import (
+	"game-server-monitor/internal/models"
+	"time"
+)
+
+const (
+	// MaxSampleGap is the longest period a single raw probe result is assumed
+	// to represent; longer gaps between probes count as unmonitored
+	MaxSampleGap = 5 * time.Minute
+	// SummaryCacheTTL is how long uptime summaries are reused
+	SummaryCacheTTL = time.Minute
+	// summarySegments is the approximate resolution targeted for summaries
+	summarySegments = 200
+)
+
+// UptimeWindows maps the standard reporting window names to their length
+var UptimeWindows = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+	"90d": 90 * 24 * time.Hour,
+}
+
+// cachedSummary is an uptime summary with its computation time
+type cachedSummary struct {
+	summary    *models.UptimeSummary
+	computedAt time.Time
+}
+
+// segment is a monitored period and the fraction of it the server was online
+type segment struct {
+	start  time.Time
+	end    time.Time
+	uptime float64
+}
+
+// Uptime computes the uptime report of a server over [from, to) from the
+// finest history still retained. The period before the server was created
+// is not applicable and neither counts as uptime nor downtime.
+func (s *Service) Uptime(server *models.Server, from, to time.Time) (*models.UptimeReport, error) {
+	return s.uptime(server, from, to, 0)
+}
+
+// UptimeSummary computes the uptime percentages of a server over the
+// standard windows. Results are cached for SummaryCacheTTL.
+func (s *Service) UptimeSummary(server *models.Server) (*models.UptimeSummary, error) {
+	s.mutex.Lock()
+	cached, found := s.summaries[server.ID]
+	s.mutex.Unlock()
+
+	if found && time.Since(cached.computedAt) < SummaryCacheTTL {
+		return cached.summary, nil
+	}
+
+	now := time.Now()
+	summary := &models.UptimeSummary{}
+	targets := map[string]**float64{
+		"24h": &summary.Day,
+		"7d":  &summary.Week,
+		"30d": &summary.Month,
+		"90d": &summary.Quarter,
+	}
+
+	for name, target := range targets {
+		window := UptimeWindows[name]
+		report, err := s.uptime(server, now.Add(-window), now, window/summarySegments)
+		if err != nil {
+			return nil, err
+		}
+		*target = report.UptimePercent
+	}
+
+	s.mutex.Lock()
+	s.summaries[server.ID] = cachedSummary{summary: summary, computedAt: now}
+	s.mutex.Unlock()
+
+	return summary, nil
+}
+
+// uptime computes an uptime report reading from the coarsest tier that fits step
+func (s *Service) uptime(server *models.Server, from, to time.Time, step time.Duration) (*models.UptimeReport, error) {
+	if !from.Before(to) {
+		return nil, &QueryError{Message: "invalid time range: from must be before to"}
+	}
+
+	report := &models.UptimeReport{
+		ServerID:  server.ID,
+		From:      from,
+		To:        to,
+		Incidents: []models.Incident{},
+	}
+
+	// Nothing to report for the part of the window before the server existed
+	start := from
+	if server.CreatedAt.After(start) {
+		start = server.CreatedAt
+	}
+	if !start.Before(to) {
+		report.Resolution = ResolutionRaw
+		return report, nil
+	}
+
+	level := s.policy.selectTier(start, step, time.Now())
+	chunks, err := s.loadRange(server.ID, level, start, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Resolution = tiers[level].name
+	computeUptime(report, buildSegments(chunks, start, to))
+
+	return report, nil
+}
+
+// buildSegments converts chunks into monitored segments clipped to [from, to)
+func buildSegments(chunks []chunk, from, to time.Time) []segment {
+	var segments []segment
+
+	add := func(start, end time.Time, uptime float64) {
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if start.Before(end) {
+			segments = append(segments, segment{start: start, end: end, uptime: uptime})
+		}
+	}
+
+	for _, c := range chunks {
+		if c.level > 0 {
+			// Time in maintenance is not monitored; shorten the bucket by its share
+			width := tiers[c.level].resolution
+			for _, part := range c.parts {
+				if monitored := part.Samples - part.MaintenanceSamples; monitored > 0 {
+					length := width * time.Duration(monitored) / time.Duration(part.Samples)
+					add(part.BucketStart, part.BucketStart.Add(length), part.Uptime)
+				}
+			}
+			continue
+		}
+
+		// A raw probe result represents the time until the next probe
+		for i, part := range c.parts {
+			if part.MaintenanceSamples > 0 {
+				continue
+			}
+			end := part.BucketStart.Add(MaxSampleGap)
+			if i+1 < len(c.parts) && c.parts[i+1].BucketStart.Before(end) {
+				end = c.parts[i+1].BucketStart
+			}
+			add(part.BucketStart, end, part.Uptime)
+		}
+	}
+
+	return segments
+}
+
+// computeUptime fills in the uptime figures and incidents of a report from
+// chronologically ordered segments. Consecutive segments with downtime are
+// merged into a single incident.
+func computeUptime(report *models.UptimeReport, segments []segment) {
+	var monitored, downtime time.Duration
+	var current *models.Incident
+	var currentEnd time.Time
+
+	closeIncident := func() {
+		end := currentEnd
+		current.End = &end
+		report.Incidents = append(report.Incidents, *current)
+		current = nil
+	}
+
+	for _, seg := range segments {
+		length := seg.end.Sub(seg.start)
+		down := time.Duration(float64(length) * (1 - seg.uptime))
+
+		monitored += length
+		downtime += down
+
+		if seg.uptime >= 1 {
+			if current != nil {
+				closeIncident()
+			}
+			continue
+		}
+
+		if current == nil {
+			current = &models.Incident{Start: seg.start}
+		}
+		current.Duration += int64(down / time.Second)
+		currentEnd = seg.end
+	}
+
+	// An incident still open at the end of the window is ongoing
+	if current != nil {
+		current.Ongoing = true
+		report.Incidents = append(report.Incidents, *current)
+	}
+
+	report.MonitoredSeconds = int64(monitored / time.Second)
+	report.DowntimeSeconds = int64(downtime / time.Second)
+	report.IncidentCount = len(report.Incidents)
+
+	if monitored > 0 {
+		percent := float64(monitored-downtime) / float64(monitored) * 100
+		report.UptimePercent = &percent
+	}
+}
@@ -0,0 +1,173 @@
+package history
+
+import (
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/models"
+	"testing"
+	"time"
+)
+
+func TestComputeUptime_RawIncidents(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Minute)
+
+	// Online, offline for two minutes, online again, then offline until the end
+	var samples []models.StatusSample
+	for i := 0; i < 10; i++ {
+		online := i < 3 || (i >= 5 && i < 8)
+		samples = append(samples, models.StatusSample{Timestamp: from.Add(time.Duration(i) * time.Minute), Online: online})
+	}
+
+	report := &models.UptimeReport{}
+	computeUptime(report, buildSegments([]chunk{{level: 0, parts: sampleRollups(samples)}}, from, to))
+
+	if report.MonitoredSeconds != 600 || report.DowntimeSeconds != 240 {
+		t.Errorf("Expected 600s monitored and 240s down, got %d and %d", report.MonitoredSeconds, report.DowntimeSeconds)
+	}
+	if report.UptimePercent == nil || *report.UptimePercent != 60 {
+		t.Errorf("Expected 60%% uptime, got %v", report.UptimePercent)
+	}
+	if report.IncidentCount != 2 {
+		t.Fatalf("Expected 2 incidents, got %d", report.IncidentCount)
+	}
+
+	first := report.Incidents[0]
+	if !first.Start.Equal(from.Add(3*time.Minute)) || first.End == nil || !first.End.Equal(from.Add(5*time.Minute)) {
+		t.Errorf("Unexpected first incident: %+v", first)
+	}
+	if first.Duration != 120 || first.Ongoing {
+		t.Errorf("Expected closed 120s incident, got %+v", first)
+	}
+
+	last := report.Incidents[1]
+	if !last.Ongoing || last.End != nil {
+		t.Errorf("Expected last incident to be ongoing, got %+v", last)
+	}
+}
+
+func TestComputeUptime_GapsAreUnmonitored(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	samples := []models.StatusSample{
+		{Timestamp: from, Online: true},
+		{Timestamp: from.Add(30 * time.Minute), Online: true},
+	}
+
+	report := &models.UptimeReport{}
+	computeUptime(report, buildSegments([]chunk{{level: 0, parts: sampleRollups(samples)}}, from, to))
+
+	if report.MonitoredSeconds != int64(2*MaxSampleGap/time.Second) {
+		t.Errorf("Expected only %v per sample to be monitored, got %ds", MaxSampleGap, report.MonitoredSeconds)
+	}
+	if report.UptimePercent == nil || *report.UptimePercent != 100 {
+		t.Errorf("Expected 100%% uptime, got %v", report.UptimePercent)
+	}
+}
+
+func TestComputeUptime_MaintenanceExcluded(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Minute)
+
+	// Offline for four minutes during maintenance, online otherwise
+	var samples []models.StatusSample
+	for i := 0; i < 10; i++ {
+		maintenance := i >= 3 && i < 7
+		samples = append(samples, models.StatusSample{
+			Timestamp:   from.Add(time.Duration(i) * time.Minute),
+			Online:      !maintenance,
+			Maintenance: maintenance,
+		})
+	}
+
+	report := &models.UptimeReport{}
+	computeUptime(report, buildSegments([]chunk{{level: 0, parts: sampleRollups(samples)}}, from, to))
+
+	if report.MonitoredSeconds != 360 || report.DowntimeSeconds != 0 || report.IncidentCount != 0 {
+		t.Errorf("Expected 360s monitored without downtime, got %+v", report)
+	}
+
+	// The same probes merged into a rollup keep full uptime over a shorter period
+	merged := mergeRollups(sampleRollups(samples))
+	if merged.Uptime != 1 || merged.MaintenanceSamples != 4 {
+		t.Errorf("Expected uptime 1 with 4 maintenance samples, got %+v", merged)
+	}
+	merged.BucketStart = from
+
+	report = &models.UptimeReport{}
+	computeUptime(report, buildSegments([]chunk{{level: 1, parts: []models.StatusRollup{merged}}}, from, to))
+	if report.UptimePercent == nil || *report.UptimePercent != 100 {
+		t.Errorf("Expected 100%% uptime, got %v", report.UptimePercent)
+	}
+}
+
+func TestComputeUptime_Rollups(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Hour)
+
+	rollups := []models.StatusRollup{
+		{BucketStart: from, Samples: 120, Uptime: 1},
+		{BucketStart: from.Add(time.Hour), Samples: 120, Uptime: 0.5},
+		{BucketStart: from.Add(2 * time.Hour), Samples: 120, Uptime: 1},
+	}
+
+	report := &models.UptimeReport{}
+	computeUptime(report, buildSegments([]chunk{{level: 2, parts: rollups}}, from, to))
+
+	if report.DowntimeSeconds != 1800 {
+		t.Errorf("Expected 1800s downtime, got %d", report.DowntimeSeconds)
+	}
+	if report.IncidentCount != 1 || report.Incidents[0].Duration != 1800 {
+		t.Errorf("Expected a single 1800s incident, got %+v", report.Incidents)
+	}
+}
+
+func TestService_UptimeExcludesTimeBeforeCreation(t *testing.T) {
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+
+	dbService := database.NewDatabaseService()
+	server, err := dbService.CreateServer(&models.CreateServerRequest{
+		Name:    "Uptime Test",
+		Type:    "minecraft",
+		Address: "localhost",
+		Port:    25565,
+	})
+	if err != nil {
+		t.Fatal("Failed to create test server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	// The server was created an hour ago and has been online ever since
+	now := time.Now()
+	server.CreatedAt = now.Add(-time.Hour)
+	for ts := server.CreatedAt; ts.Before(now); ts = ts.Add(time.Minute) {
+		status := &models.ServerStatus{Online: true, LastUpdated: ts}
+		if err := dbService.RecordStatusSample(server.ID, status); err != nil {
+			t.Fatal("Failed to record sample:", err)
+		}
+	}
+
+	service := NewServiceWithPolicy(dbService, DefaultRetentionPolicy())
+
+	report, err := service.Uptime(server, now.Add(-24*time.Hour), now)
+	if err != nil {
+		t.Fatal("Failed to calculate uptime:", err)
+	}
+	if report.UptimePercent == nil || *report.UptimePercent != 100 {
+		t.Errorf("Expected 100%% uptime, got %v", report.UptimePercent)
+	}
+	if report.MonitoredSeconds > 3600 {
+		t.Errorf("Expected at most an hour monitored, got %ds", report.MonitoredSeconds)
+	}
+
+	// A window entirely before creation has no data rather than downtime
+	report, err = service.Uptime(server, now.Add(-48*time.Hour), now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal("Failed to calculate uptime:", err)
+	}
+	if report.UptimePercent != nil || report.DowntimeSeconds != 0 {
+		t.Errorf("Expected no uptime data before creation, got %+v", report)
+	}
+}
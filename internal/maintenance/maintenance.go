@@ -0,0 +1,159 @@
+// Package maintenance resolves which servers are in a planned maintenance
+// window at a given time and when their next window starts.
+package maintenance
+
+import (
+	"game-server-monitor/internal/cron"
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/models"
+	"time"
+)
+
+// Service answers maintenance queries from the enabled windows in the database
+type Service struct {
+	dbService *database.DatabaseService
+}
+
+// NewService creates a new maintenance Service
+func NewService(dbService *database.DatabaseService) *Service {
+	return &Service{dbService: dbService}
+}
+
+// Active returns the maintenance period a server is in at the given time, or nil
+func (s *Service) Active(serverID uint, at time.Time) (*models.MaintenancePeriod, error) {
+	windows, err := s.dbService.GetEnabledMaintenanceWindows()
+	if err != nil {
+		return nil, err
+	}
+	return ActivePeriod(windows, serverID, at), nil
+}
+
+// Next returns the first maintenance period of a server starting after the
+// given time, or nil if none is planned
+func (s *Service) Next(serverID uint, after time.Time) (*models.MaintenancePeriod, error) {
+	windows, err := s.dbService.GetEnabledMaintenanceWindows()
+	if err != nil {
+		return nil, err
+	}
+	return NextPeriod(windows, serverID, after), nil
+}
+
+// ActivePeriod returns the period of the given windows that covers a server
+// at the given time. When several windows overlap the one ending last wins.
+func ActivePeriod(windows []models.MaintenanceWindow, serverID uint, at time.Time) *models.MaintenancePeriod {
+	var active *models.MaintenancePeriod
+	for i := range windows {
+		window := &windows[i]
+		if !Applies(window, serverID) {
+			continue
+		}
+		if period := occurrence(window, at); period != nil && (active == nil || period.End.After(active.End)) {
+			active = period
+		}
+	}
+	return active
+}
+
+// NextPeriod returns the earliest period of the given windows that starts
+// after the given time for a server
+func NextPeriod(windows []models.MaintenanceWindow, serverID uint, after time.Time) *models.MaintenancePeriod {
+	var next *models.MaintenancePeriod
+	for i := range windows {
+		window := &windows[i]
+		if !Applies(window, serverID) {
+			continue
+		}
+		if period := nextOccurrence(window, after); period != nil && (next == nil || period.Start.Before(next.Start)) {
+			next = period
+		}
+	}
+	return next
+}
+
+// Applies reports whether a window affects a server; windows without
+// servers are global
+func Applies(window *models.MaintenanceWindow, serverID uint) bool {
+	if len(window.ServerIDs) == 0 {
+		return true
+	}
+	for _, id := range window.ServerIDs {
+		if id == serverID {
+			return true
+		}
+	}
+	return false
+}
+
+// occurrence returns the period of a window containing at, or nil
+func occurrence(window *models.MaintenanceWindow, at time.Time) *models.MaintenancePeriod {
+	if window.Cron == "" {
+		if window.StartsAt == nil || window.EndsAt == nil {
+			return nil
+		}
+		if at.Before(*window.StartsAt) || !at.Before(*window.EndsAt) {
+			return nil
+		}
+		return period(window, *window.StartsAt, *window.EndsAt)
+	}
+
+	schedule, location, ok := recurrence(window)
+	if !ok {
+		return nil
+	}
+
+	// The earliest start within one duration before at is still running
+	duration := time.Duration(window.DurationMinutes) * time.Minute
+	start := schedule.Next(at.In(location).Add(-duration))
+	if start.IsZero() || start.After(at) {
+		return nil
+	}
+	return period(window, start, start.Add(duration))
+}
+
+// nextOccurrence returns the first period of a window starting after the given time, or nil
+func nextOccurrence(window *models.MaintenanceWindow, after time.Time) *models.MaintenancePeriod {
+	if window.Cron == "" {
+		if window.StartsAt == nil || window.EndsAt == nil || !window.StartsAt.After(after) {
+			return nil
+		}
+		return period(window, *window.StartsAt, *window.EndsAt)
+	}
+
+	schedule, location, ok := recurrence(window)
+	if !ok {
+		return nil
+	}
+
+	start := schedule.Next(after.In(location))
+	if start.IsZero() {
+		return nil
+	}
+	return period(window, start, start.Add(time.Duration(window.DurationMinutes)*time.Minute))
+}
+
+// recurrence parses the schedule and time zone of a recurring window
+func recurrence(window *models.MaintenanceWindow) (*cron.Schedule, *time.Location, bool) {
+	schedule, err := cron.Parse(window.Cron)
+	if err != nil || window.DurationMinutes <= 0 {
+		return nil, nil, false
+	}
+
+	location := time.Local
+	if window.Timezone != "" {
+		if location, err = time.LoadLocation(window.Timezone); err != nil {
+			return nil, nil, false
+		}
+	}
+	return schedule, location, true
+}
+
+// period builds a MaintenancePeriod for a window
+func period(window *models.MaintenanceWindow, start, end time.Time) *models.MaintenancePeriod {
+	return &models.MaintenancePeriod{
+		WindowID:    window.ID,
+		Title:       window.Title,
+		Description: window.Description,
+		Start:       start,
+		End:         end,
+	}
+}
@@ -0,0 +1,72 @@
+package maintenance
+
+import (
+	"game-server-monitor/internal/models"
+	"testing"
+	"time"
+)
+
+func TestActivePeriod(t *testing.T) {
+	start := time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	windows := []models.MaintenanceWindow{
+		{ID: 1, Title: "Hardware swap", ServerIDs: []uint{1}, StartsAt: &start, EndsAt: &end},
+		{ID: 2, Title: "Nightly restart", Cron: "0 4 * * *", DurationMinutes: 30, Timezone: "UTC"},
+	}
+
+	tests := []struct {
+		name     string
+		serverID uint
+		at       time.Time
+		want     uint // Window ID, 0 for none
+	}{
+		{"one-off start", 1, start, 1},
+		{"one-off end", 1, end, 0},
+		{"one-off other server", 2, start.Add(time.Hour), 0},
+		{"recurring start", 2, time.Date(2024, 5, 2, 4, 0, 0, 0, time.UTC), 2},
+		{"recurring running", 3, time.Date(2024, 5, 2, 4, 29, 59, 0, time.UTC), 2},
+		{"recurring over", 3, time.Date(2024, 5, 2, 4, 30, 0, 0, time.UTC), 0},
+		{"recurring before", 3, time.Date(2024, 5, 2, 3, 59, 0, 0, time.UTC), 0},
+	}
+
+	for _, tt := range tests {
+		period := ActivePeriod(windows, tt.serverID, tt.at)
+		var got uint
+		if period != nil {
+			got = period.WindowID
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected window %d, got %d", tt.name, tt.want, got)
+		}
+	}
+
+	period := ActivePeriod(windows, 5, time.Date(2024, 5, 2, 4, 10, 0, 0, time.UTC))
+	if period == nil || !period.End.Equal(time.Date(2024, 5, 2, 4, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected recurring period to end at 04:30, got %+v", period)
+	}
+}
+
+func TestNextPeriod(t *testing.T) {
+	start := time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	windows := []models.MaintenanceWindow{
+		{ID: 1, Title: "Hardware swap", ServerIDs: []uint{1}, StartsAt: &start, EndsAt: &end},
+		{ID: 2, Title: "Weekly update", Cron: "0 6 * * 3", DurationMinutes: 60, Timezone: "UTC"},
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) // Wednesday
+
+	if next := NextPeriod(windows, 1, now); next == nil || next.WindowID != 1 {
+		t.Errorf("Expected the one-off window next, got %+v", next)
+	}
+
+	next := NextPeriod(windows, 2, now)
+	if next == nil || next.WindowID != 2 || !next.Start.Equal(time.Date(2024, 5, 8, 6, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected next Wednesday's update, got %+v", next)
+	}
+
+	if next := NextPeriod(windows[:1], 1, start); next != nil {
+		t.Errorf("Expected no window after the one-off started, got %+v", next)
+	}
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+)
+
+// Alert rule metrics, read from ServerStatus
+const (
+	MetricOnline        = "online"         // 1 when online, 0 when offline
+	MetricPlayers       = "players"        // Current player count
+	MetricMaxPlayers    = "max_players"    // Player slots
+	MetricPlayerPercent = "player_percent" // Players as percentage of max players
+	MetricPing          = "ping"           // Response time in ms
+)
+
+// Alert rule comparators
+const (
+	ComparatorGreater      = ">"
+	ComparatorGreaterEqual = ">="
+	ComparatorLess         = "<"
+	ComparatorLessEqual    = "<="
+	ComparatorEqual        = "=="
+	ComparatorNotEqual     = "!="
+)
+
+// Alert states of a rule for one server
+const (
+	AlertStateInactive = "inactive" // Condition not met
+	AlertStatePending  = "pending"  // Condition met, waiting for the for-duration / consecutive probes
+	AlertStateFiring   = "firing"
+	AlertStateResolved = "resolved" // Condition no longer met after firing
+)
+
+// AlertRule fires when a status metric of the selected servers meets a
+// condition for a duration, e.g. "ping > 150 for 5 minutes"
+type AlertRule struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	Name              string    `gorm:"not null" json:"name"`
+	Enabled           bool      `gorm:"not null" json:"enabled"`
+	ServerIDs         []uint    `gorm:"serializer:json" json:"server_ids"`    // Selected servers
+	ServerGroups      []string  `gorm:"serializer:json" json:"server_groups"` // Selected server groups
+	ServerTypes       []string  `gorm:"serializer:json" json:"server_types"`  // Selected server types, all selectors empty selects every server
+	Metric            string    `gorm:"not null" json:"metric"`
+	Comparator        string    `gorm:"not null" json:"comparator"`
+	Threshold         float64   `json:"threshold"`
+	ForSeconds        int64     `json:"for_seconds"`        // How long the condition must hold before firing
+	ConsecutiveProbes int       `json:"consecutive_probes"` // How many probes in a row must meet the condition
+	ActiveFrom        string    `json:"active_from"`        // Optional daily window start (HH:MM, server local time)
+	ActiveTo          string    `json:"active_to"`          // Optional daily window end (HH:MM), may wrap past midnight
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// AlertState tracks the state of an alert rule for one server
+type AlertState struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	RuleID          uint       `gorm:"not null;uniqueIndex:idx_alert_states_rule_server" json:"rule_id"`
+	ServerID        uint       `gorm:"not null;uniqueIndex:idx_alert_states_rule_server" json:"server_id"`
+	State           string     `gorm:"not null;index" json:"state"`
+	Value           float64    `json:"value"` // Metric value at the last evaluation
+	ConsecutiveHits int        `json:"consecutive_hits"`
+	PendingSince    *time.Time `json:"pending_since"`
+	FiredAt         *time.Time `json:"fired_at"`
+	ResolvedAt      *time.Time `json:"resolved_at"`
+	LastEvaluated   time.Time  `json:"last_evaluated"` // Timestamp of the last evaluated probe result
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// CreateAlertRuleRequest represents the request to create an alert rule
+type CreateAlertRuleRequest struct {
+	Name              string   `json:"name" binding:"required"`
+	Enabled           *bool    `json:"enabled"` // Defaults to true
+	ServerIDs         []uint   `json:"server_ids"`
+	ServerGroups      []string `json:"server_groups"`
+	ServerTypes       []string `json:"server_types"`
+	Metric            string   `json:"metric" binding:"required,oneof=online players max_players player_percent ping"`
+	Comparator        string   `json:"comparator" binding:"required,oneof=> >= < <= == !="`
+	Threshold         float64  `json:"threshold"`
+	ForSeconds        int64    `json:"for_seconds" binding:"min=0"`
+	ConsecutiveProbes int      `json:"consecutive_probes" binding:"min=0"`
+	ActiveFrom        string   `json:"active_from"`
+	ActiveTo          string   `json:"active_to"`
+}
+
+// UpdateAlertRuleRequest represents the request to update an alert rule
+type UpdateAlertRuleRequest struct {
+	Name              string   `json:"name" binding:"required"`
+	Enabled           *bool    `json:"enabled"` // Defaults to true
+	ServerIDs         []uint   `json:"server_ids"`
+	ServerGroups      []string `json:"server_groups"`
+	ServerTypes       []string `json:"server_types"`
+	Metric            string   `json:"metric" binding:"required,oneof=online players max_players player_percent ping"`
+	Comparator        string   `json:"comparator" binding:"required,oneof=> >= < <= == !="`
+	Threshold         float64  `json:"threshold"`
+	ForSeconds        int64    `json:"for_seconds" binding:"min=0"`
+	ConsecutiveProbes int      `json:"consecutive_probes" binding:"min=0"`
+	ActiveFrom        string   `json:"active_from"`
+	ActiveTo          string   `json:"active_to"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+)
+
+// StatusSample is a single persisted probe result
+type StatusSample struct {
+	ID          uint      `gorm:"primaryKey" json:"-"`
+	ServerID    uint      `gorm:"not null;index:idx_status_samples_server_time,priority:1" json:"server_id"`
+	Timestamp   time.Time `gorm:"not null;index:idx_status_samples_server_time,priority:2" json:"timestamp"`
+	Online      bool      `json:"online"`
+	Players     int       `json:"players"`
+	MaxPlayers  int       `json:"max_players"`
+	Ping        int64     `json:"ping"` // Response time (ms)
+	Version     string    `json:"version"`
+	Maintenance bool      `json:"maintenance"` // Probed during a maintenance window
+}
+
+// HistoryPoint is one bucket of an aggregated status series
+type HistoryPoint struct {
+	Timestamp  time.Time `json:"timestamp"`   // Bucket start
+	Samples    int       `json:"samples"`     // Number of probes in the bucket (0 = no data)
+	Uptime     float64   `json:"uptime"`      // Fraction of probes outside maintenance that were online (0-1)
+	MinPlayers int       `json:"min_players"` // Lowest player count seen
+	MaxPlayers int       `json:"max_players"` // Highest player count seen
+	AvgPlayers float64   `json:"avg_players"` // Average player count
+	Capacity   int       `json:"capacity"`    // Highest reported player capacity
+	AvgPing    float64   `json:"avg_ping"`    // Average ping of online probes (ms)
+	P95Ping    float64   `json:"p95_ping"`    // 95th percentile ping of online probes (ms)
+	Version    string    `json:"version"`     // Last version seen in the bucket
+
+	MaintenanceSamples int `json:"maintenance_samples"` // Probes during maintenance, excluded from uptime
+}
+
+// HistoryResponse represents the response for the status history API
+type HistoryResponse struct {
+	ServerID   uint           `json:"server_id"`
+	From       time.Time      `json:"from"`
+	To         time.Time      `json:"to"`
+	Step       int64          `json:"step"`       // Bucket width (seconds)
+	Resolution string         `json:"resolution"` // Storage tier the series was built from ("raw" or a rollup tier)
+	Points     []HistoryPoint `json:"points"`
+}
+
+// Rollup tiers, from finest to coarsest
+const (
+	RollupTier5m = "5m"
+	RollupTier1h = "1h"
+	RollupTier1d = "1d"
+)
+
+// StatusRollup aggregates the probe results of a server over a fixed bucket
+type StatusRollup struct {
+	ID            uint      `gorm:"primaryKey" json:"-"`
+	ServerID      uint      `gorm:"not null;uniqueIndex:idx_status_rollups_bucket,priority:1" json:"server_id"`
+	Tier          string    `gorm:"not null;uniqueIndex:idx_status_rollups_bucket,priority:2" json:"tier"`
+	BucketStart   time.Time `gorm:"not null;uniqueIndex:idx_status_rollups_bucket,priority:3" json:"bucket_start"`
+	Samples       int       `json:"samples"`        // Number of probes aggregated
+	OnlineSamples int       `json:"online_samples"` // Number of probes that were online
+	MinPlayers    int       `json:"min_players"`
+	MaxPlayers    int       `json:"max_players"`
+	AvgPlayers    float64   `json:"avg_players"`
+	Capacity      int       `json:"capacity"` // Highest reported player capacity
+	AvgPing       float64   `json:"avg_ping"` // Average ping of online probes (ms)
+	P95Ping       float64   `json:"p95_ping"` // 95th percentile ping of online probes (ms)
+	Uptime        float64   `json:"uptime"`   // Fraction of probes outside maintenance that were online (0-1)
+	Version       string    `json:"version"`
+
+	MaintenanceSamples int `json:"maintenance_samples"` // Number of probes during maintenance
+}
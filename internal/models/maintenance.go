@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+// MaintenanceWindow is a planned period during which servers are expected to
+// be down. A window is either one-off (StartsAt/EndsAt) or recurring (Cron
+// plus DurationMinutes). Servers in maintenance are excluded from uptime and
+// do not trigger notifications or alerts.
+type MaintenanceWindow struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	Title           string     `gorm:"not null" json:"title"`
+	Description     string     `json:"description"`
+	ServerIDs       []uint     `gorm:"serializer:json" json:"server_ids"` // Affected servers, empty affects every server
+	StartsAt        *time.Time `json:"starts_at"`                         // One-off window start
+	EndsAt          *time.Time `json:"ends_at"`                           // One-off window end
+	Cron            string     `json:"cron"`                              // Recurring window start, e.g. "0 4 * * 1"
+	DurationMinutes int        `json:"duration_minutes"`                  // Recurring window length
+	Timezone        string     `json:"timezone"`                          // IANA zone of the cron expression, empty for server local time
+	Enabled         bool       `gorm:"not null" json:"enabled"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// MaintenancePeriod is one occurrence of a maintenance window
+type MaintenancePeriod struct {
+	WindowID    uint      `json:"window_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+}
+
+// CreateMaintenanceWindowRequest represents the request to create a maintenance window
+type CreateMaintenanceWindowRequest struct {
+	Title           string     `json:"title" binding:"required"`
+	Description     string     `json:"description"`
+	ServerIDs       []uint     `json:"server_ids"`
+	StartsAt        *time.Time `json:"starts_at"`
+	EndsAt          *time.Time `json:"ends_at"`
+	Cron            string     `json:"cron"`
+	DurationMinutes int        `json:"duration_minutes" binding:"min=0"`
+	Timezone        string     `json:"timezone"`
+	Enabled         *bool      `json:"enabled"` // Defaults to true
+}
+
+// UpdateMaintenanceWindowRequest represents the request to update a maintenance window
+type UpdateMaintenanceWindowRequest struct {
+	Title           string     `json:"title" binding:"required"`
+	Description     string     `json:"description"`
+	ServerIDs       []uint     `json:"server_ids"`
+	StartsAt        *time.Time `json:"starts_at"`
+	EndsAt          *time.Time `json:"ends_at"`
+	Cron            string     `json:"cron"`
+	DurationMinutes int        `json:"duration_minutes" binding:"min=0"`
+	Timezone        string     `json:"timezone"`
+	Enabled         *bool      `json:"enabled"` // Defaults to true
+}
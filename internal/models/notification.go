@@ -0,0 +1,109 @@
+package models
+
+import (
+	"time"
+)
+
+// Notification channel types
+const (
+	ChannelTypeWebhook = "webhook"
+	ChannelTypeDiscord = "discord"
+	ChannelTypeSlack   = "slack"
+	ChannelTypeEmail   = "email"
+)
+
+// Email digest periods
+const (
+	DigestHourly = "hourly"
+	DigestDaily  = "daily"
+)
+
+// Notification delivery outcomes
+const (
+	DeliveryStatusSuccess = "success"
+	DeliveryStatusFailed  = "failed"
+)
+
+// NotificationChannel is a destination for server state-change notifications
+type NotificationChannel struct {
+	ID           uint     `gorm:"primaryKey" json:"id"`
+	Name         string   `gorm:"not null" json:"name"`
+	Type         string   `gorm:"not null" json:"type"`                 // "webhook", "discord", "slack" or "email"
+	URL          string   `json:"url"`                                  // Endpoint notifications are posted to
+	Secret       string   `json:"-"`                                    // HMAC signing key, never returned to frontend
+	Events       []string `gorm:"serializer:json" json:"events"`        // Event types to notify about, empty for all (email: outages and recoveries)
+	ServerIDs    []uint   `gorm:"serializer:json" json:"server_ids"`    // Servers to notify about
+	ServerGroups []string `gorm:"serializer:json" json:"server_groups"` // Server groups to notify about
+	Template     string   `gorm:"type:text" json:"template"`            // Go text/template for the message, empty for the default
+	Enabled      bool     `gorm:"not null" json:"enabled"`
+
+	// Email channel settings
+	SMTPHost     string   `json:"smtp_host"`
+	SMTPPort     int      `json:"smtp_port"`
+	SMTPStartTLS bool     `json:"smtp_starttls"`
+	SMTPUsername string   `json:"smtp_username"`
+	SMTPPassword string   `json:"-"` // Never returned to frontend
+	EmailFrom    string   `json:"email_from"`
+	EmailTo      []string `gorm:"serializer:json" json:"email_to"`
+	Digest       string   `json:"digest"` // "", "hourly" or "daily"
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NotificationDelivery records the outcome of delivering one event to a channel
+type NotificationDelivery struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ChannelID    uint      `gorm:"not null;index" json:"channel_id"`
+	ServerID     uint      `json:"server_id"`
+	EventType    string    `json:"event_type"`
+	Status       string    `json:"status"` // "success" or "failed"
+	Attempts     int       `json:"attempts"`
+	ResponseCode int       `json:"response_code"` // Last HTTP status or SMTP reply code, 0 if no response
+	Error        string    `json:"error"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// CreateNotificationChannelRequest represents the request to create a notification channel
+type CreateNotificationChannelRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	Type         string   `json:"type" binding:"required,oneof=webhook discord slack email"`
+	URL          string   `json:"url" binding:"omitempty,url"` // Required for all types except email
+	Secret       string   `json:"secret"`
+	Events       []string `json:"events"`
+	ServerIDs    []uint   `json:"server_ids"`
+	ServerGroups []string `json:"server_groups"`
+	Template     string   `json:"template"`
+	Enabled      *bool    `json:"enabled"` // Defaults to true
+
+	SMTPHost     string   `json:"smtp_host"`
+	SMTPPort     int      `json:"smtp_port" binding:"omitempty,min=1,max=65535"` // Defaults to 587
+	SMTPStartTLS bool     `json:"smtp_starttls"`
+	SMTPUsername string   `json:"smtp_username"`
+	SMTPPassword string   `json:"smtp_password"`
+	EmailFrom    string   `json:"email_from" binding:"omitempty,email"`
+	EmailTo      []string `json:"email_to" binding:"dive,email"`
+	Digest       string   `json:"digest" binding:"omitempty,oneof=hourly daily"`
+}
+
+// UpdateNotificationChannelRequest represents the request to update a notification channel
+type UpdateNotificationChannelRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	Type         string   `json:"type" binding:"required,oneof=webhook discord slack email"`
+	URL          string   `json:"url" binding:"omitempty,url"` // Required for all types except email
+	Secret       string   `json:"secret"`                      // Empty keeps the current secret
+	Events       []string `json:"events"`
+	ServerIDs    []uint   `json:"server_ids"`
+	ServerGroups []string `json:"server_groups"`
+	Template     string   `json:"template"`
+	Enabled      *bool    `json:"enabled"` // Defaults to true
+
+	SMTPHost     string   `json:"smtp_host"`
+	SMTPPort     int      `json:"smtp_port" binding:"omitempty,min=1,max=65535"` // Defaults to 587
+	SMTPStartTLS bool     `json:"smtp_starttls"`
+	SMTPUsername string   `json:"smtp_username"`
+	SMTPPassword string   `json:"smtp_password"` // Empty keeps the current password
+	EmailFrom    string   `json:"email_from" binding:"omitempty,email"`
+	EmailTo      []string `json:"email_to" binding:"dive,email"`
+	Digest       string   `json:"digest" binding:"omitempty,oneof=hourly daily"`
+}
@@ -0,0 +1,6 @@
+package models
+
+// ProbeIntervalRequest represents the request to change the default probe interval
+type ProbeIntervalRequest struct {
+	IntervalSeconds int `json:"interval_seconds" binding:"required,min=5,max=86400"`
+}
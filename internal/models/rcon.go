@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// RCONAuditEntry records one RCON command requested by an admin
+type RCONAuditEntry struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ServerID  uint      `gorm:"not null;index" json:"server_id"`
+	UserID    uint      `json:"user_id"`
+	Username  string    `json:"username"`
+	Command   string    `json:"command"`
+	Status    string    `json:"status"` // "success", "failed" or "denied"
+	Output    string    `gorm:"type:text" json:"output"`
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RCON audit statuses
+const (
+	RCONStatusSuccess = "success"
+	RCONStatusFailed  = "failed"
+	RCONStatusDenied  = "denied" // Not on the server's allowlist
+)
+
+// RCONRequest represents the request to execute an RCON command
+type RCONRequest struct {
+	Command string `json:"command" binding:"required"`
+}
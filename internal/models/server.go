@@ -6,17 +6,47 @@ import (
 
 // Server represents a game server configuration
 type Server struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Name        string    `gorm:"not null" json:"name"`
-	Type        string    `gorm:"not null" json:"type"`       // "minecraft" or "cs2"
-	Address     string    `gorm:"not null" json:"address"`    // IP or domain
-	Port        int       `gorm:"not null" json:"port"`       // Game port
-	Description string    `json:"description"`                // Server description
-	DownloadURL string    `json:"download_url"`               // Client download link
-	Changelog   string    `gorm:"type:text" json:"changelog"` // Update log (Markdown)
-	Version     string    `json:"version"`                    // Detected version
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"not null" json:"name"`
+	Type        string `gorm:"not null" json:"type"`       // Registered server type, e.g. "minecraft"
+	Address     string `gorm:"not null" json:"address"`    // IP or domain
+	Port        int    `gorm:"not null" json:"port"`       // Game port, 0 to locate the server via its SRV record
+	QueryPort   int    `json:"query_port"`                 // Optional query port, 0 uses the game's default
+	Description string `json:"description"`                // Server description
+	DownloadURL string `json:"download_url"`               // Client download link
+	Changelog   string `gorm:"type:text" json:"changelog"` // Update log (Markdown)
+	Version     string `json:"version"`                    // Detected version
+	Group       string `gorm:"index" json:"group"`         // Optional server group, e.g. "survival"
+	EnableQuery bool   `json:"enable_query"`               // Run optional queries: Minecraft GameSpy4 player list, Source A2S_RULES
+	HidePlayers bool   `json:"hide_players"`               // Do not publish player names
+	DualStack   bool   `json:"dual_stack"`                 // Probe IPv4 and IPv6 separately
+
+	// RCON settings, only published to admins via AdminServer
+	RCONPort     int      `json:"-"`                        // Optional RCON port, 0 uses the game's default
+	RCONPassword string   `json:"-"`                        // Encrypted RCON password, never returned to frontend
+	RCONCommands []string `gorm:"serializer:json" json:"-"` // Allowed RCON commands, see rcon.Allowed
+
+	Check HealthCheck `gorm:"serializer:json" json:"check"` // Settings of the tcp, udp, http and https check types
+
+	// Probe schedule and retry policy, 0 uses the prober's defaults
+	ProbeIntervalSeconds int `json:"probe_interval_seconds"` // Time between probes
+	ProbeTimeoutSeconds  int `json:"probe_timeout_seconds"`  // Timeout of a single probe attempt
+	ProbeRetries         int `json:"probe_retries"`          // Attempts before the server counts as offline
+	RetryBackoffSeconds  int `json:"retry_backoff_seconds"`  // Wait after the first failed attempt, doubled after each further one
+
+	Family    string    `gorm:"-" json:"-"` // Restricts a probe to FamilyIPv4 or FamilyIPv6, set by dual-stack probing
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HealthCheck configures the generic service checks. Fields that do not
+// apply to a check type are ignored.
+type HealthCheck struct {
+	Path         string `json:"path,omitempty"`          // HTTP request path, defaults to "/"
+	ExpectStatus int    `json:"expect_status,omitempty"` // Expected HTTP status code, 0 accepts any below 400
+	SendHex      string `json:"send_hex,omitempty"`      // Hex encoded UDP payload
+	Expect       string `json:"expect,omitempty"`        // Substring the UDP response or HTTP body must contain
+	ExpectRegex  string `json:"expect_regex,omitempty"`  // Regular expression the UDP response or HTTP body must match
 }
 
 // User represents an admin user
@@ -30,18 +60,133 @@ type User struct {
 
 // ServerStatus represents the current status of a game server
 type ServerStatus struct {
-	Online      bool      `json:"online"`
-	Players     int       `json:"players"`
-	MaxPlayers  int       `json:"max_players"`
-	Version     string    `json:"version"`
-	Ping        int64     `json:"ping"` // Response time (ms)
-	LastUpdated time.Time `json:"last_updated"`
+	Online      bool              `json:"online"`
+	Players     int               `json:"players"`
+	MaxPlayers  int               `json:"max_players"`
+	Version     string            `json:"version"`
+	Ping        int64             `json:"ping"`                // Response time (ms)
+	MOTD        string            `json:"motd,omitempty"`      // Message of the day without formatting
+	GameMode    string            `json:"game_mode,omitempty"` // Advertised game mode (Bedrock)
+	Protocol    int               `json:"protocol,omitempty"`  // Protocol version number
+	Source      *SourceInfo       `json:"source,omitempty"`    // A2S_INFO details of Source engine servers
+	Bedrock     *BedrockInfo      `json:"bedrock,omitempty"`   // Pong details of Minecraft Bedrock servers
+	PlayerList  []Player          `json:"-"`                   // Online players, published via ServerStatusResponse
+	Rules       map[string]string `json:"-"`                   // Server rules (A2S_RULES), published via ServerStatusResponse
+	Flapping    bool              `json:"flapping"`            // Bouncing between online and offline
+	Maintenance bool              `json:"maintenance"`         // Probed during a maintenance window
+	LastUpdated time.Time         `json:"last_updated"`
+
+	ResolvedAddress string `json:"resolved_address,omitempty"` // IP and port actually probed
+	SRVTarget       string `json:"srv_target,omitempty"`       // Host and port from the SRV record, if one was used
+
+	// Per-family results of dual-stack servers
+	IPv4               *FamilyStatus `json:"ipv4,omitempty"`
+	IPv6               *FamilyStatus `json:"ipv6,omitempty"`
+	PartiallyReachable bool          `json:"partially_reachable,omitempty"` // Reachable over only one of IPv4 and IPv6
+
+	// Minecraft Java Edition details
+	MOTDFormatted      string `json:"motd_formatted,omitempty"`       // MOTD with § formatting codes
+	MOTDHTML           string `json:"motd_html,omitempty"`            // MOTD rendered as HTML
+	EnforcesSecureChat *bool  `json:"enforces_secure_chat,omitempty"` // Only players with signed chat may join
+	ModLoader          string `json:"mod_loader,omitempty"`           // Forge network version, e.g. FML3
+	Mods               []Mod  `json:"-"`                              // Installed mods, published via ServerStatusResponse
+	Icon               []byte `json:"-"`                              // Server icon (PNG), stored as ServerIcon
+
+	// HTTP(S) check details
+	HTTPStatus int      `json:"http_status,omitempty"` // Response status code
+	TLS        *TLSInfo `json:"tls,omitempty"`         // Certificate presented by HTTPS servers
+}
+
+// TLSInfo describes the certificate presented by a TLS server
+type TLSInfo struct {
+	Subject       string    `json:"subject"` // Common name of the certificate
+	Issuer        string    `json:"issuer"`
+	NotAfter      time.Time `json:"not_after"`
+	DaysRemaining int       `json:"days_remaining"`  // Whole days until the certificate expires, negative once expired
+	Error         string    `json:"error,omitempty"` // Why the certificate failed verification
+}
+
+// IP families a probe can be restricted to, named like the networks of the net package
+const (
+	FamilyIPv4 = "ip4"
+	FamilyIPv6 = "ip6"
+)
+
+// FamilyStatus is the result of probing a server over one IP family
+type FamilyStatus struct {
+	Online  bool   `json:"online"`
+	Ping    int64  `json:"ping"`              // Response time (ms)
+	Address string `json:"address,omitempty"` // IP and port probed
+	Error   string `json:"error,omitempty"`   // Why the probe failed
+}
+
+// Mod is a mod installed on a modded server
+type Mod struct {
+	ID      string `json:"id"`
+	Version string `json:"version,omitempty"`
+}
+
+// ServerIcon is the last icon reported by a server. It is kept in the
+// database so it can be served across restarts and while the server is offline.
+type ServerIcon struct {
+	ServerID  uint      `gorm:"primaryKey;autoIncrement:false" json:"server_id"`
+	PNG       []byte    `gorm:"not null" json:"-"`
+	Hash      string    `gorm:"not null" json:"hash"` // Hex SHA-256 of the PNG, used as ETag
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Player is an online player reported by a server
+type Player struct {
+	Name     string  `json:"name"`
+	ID       string  `json:"id,omitempty"`       // UUID, when the protocol reports one
+	Score    int     `json:"score,omitempty"`    // Score (Source)
+	Duration float64 `json:"duration,omitempty"` // Seconds connected (Source)
+}
+
+// SourceInfo holds the A2S_INFO details of a Source engine server
+type SourceInfo struct {
+	Name        string `json:"name"` // Advertised server name
+	Map         string `json:"map"`
+	Folder      string `json:"folder"` // Game directory, e.g. "csgo"
+	Game        string `json:"game"`   // Game description or mode
+	AppID       uint64 `json:"app_id"`
+	Bots        int    `json:"bots"`
+	ServerType  string `json:"server_type"` // Dedicated, Non-Dedicated or SourceTV
+	Environment string `json:"environment"` // Linux, Windows or Mac
+	Private     bool   `json:"private"`     // Password protected
+	VAC         bool   `json:"vac"`
+	Keywords    string `json:"keywords,omitempty"`
+	Queue       int    `json:"queue,omitempty"` // Players waiting to join (Rust)
 }
 
+// BedrockInfo holds the details a Minecraft Bedrock server advertises in
+// its unconnected pong
+type BedrockInfo struct {
+	Edition   string `json:"edition"`              // MCPE, or MCEE for Education Edition
+	LevelName string `json:"level_name,omitempty"` // Second MOTD line
+	PortIPv4  int    `json:"port_ipv4,omitempty"`  // Advertised IPv4 game port
+	PortIPv6  int    `json:"port_ipv6,omitempty"`  // Advertised IPv6 game port
+}
+
+// Server states reported by the public API
+const (
+	StateOnline      = "online"
+	StateOffline     = "offline"
+	StateMaintenance = "maintenance" // In a planned maintenance window
+)
+
 // ServerStatusResponse combines server config with current status
 type ServerStatusResponse struct {
 	Server
-	Status ServerStatus `json:"status"`
+	Status          ServerStatus       `json:"status"`
+	State           string             `json:"state"`                      // online, offline or maintenance
+	PlayersList     []Player           `json:"players_list,omitempty"`     // Online players (server detail only)
+	Rules           map[string]string  `json:"rules,omitempty"`            // Server rules (server detail only)
+	Mods            []Mod              `json:"mods,omitempty"`             // Installed mods (server detail only)
+	IconURL         string             `json:"icon_url,omitempty"`         // Last known server icon
+	Uptime          *UptimeSummary     `json:"uptime,omitempty"`           // Uptime over the standard windows (server detail only)
+	Maintenance     *MaintenancePeriod `json:"maintenance,omitempty"`      // Current maintenance period
+	NextMaintenance *MaintenancePeriod `json:"next_maintenance,omitempty"` // Upcoming maintenance period
 }
 
 // ServerListResponse represents the response for server list API
@@ -50,26 +195,75 @@ type ServerListResponse struct {
 	Total   int                    `json:"total"`
 }
 
+// AdminServer is a server configuration as returned by the admin endpoints,
+// including its RCON settings
+type AdminServer struct {
+	Server
+	RCONPort     int      `json:"rcon_port"`
+	RCONCommands []string `json:"rcon_commands"`
+}
+
+// NewAdminServer wraps a server for the admin endpoints
+func NewAdminServer(server *Server) AdminServer {
+	return AdminServer{
+		Server:       *server,
+		RCONPort:     server.RCONPort,
+		RCONCommands: server.RCONCommands,
+	}
+}
+
 // CreateServerRequest represents the request to create a new server
 type CreateServerRequest struct {
 	Name        string `json:"name" binding:"required"`
-	Type        string `json:"type" binding:"required,oneof=minecraft cs2"`
+	Type        string `json:"type" binding:"required"` // A registered server type, see GET /api/server-types
 	Address     string `json:"address" binding:"required"`
-	Port        int    `json:"port" binding:"required,min=1,max=65535"`
+	Port        int    `json:"port" binding:"omitempty,min=1,max=65535"` // Optional for types with SRV lookup
+	QueryPort   int    `json:"query_port" binding:"omitempty,min=1,max=65535"`
 	Description string `json:"description"`
 	DownloadURL string `json:"download_url"`
 	Changelog   string `json:"changelog"`
+	Group       string `json:"group"`
+	EnableQuery bool   `json:"enable_query"`
+	HidePlayers bool   `json:"hide_players"`
+	DualStack   bool   `json:"dual_stack"`
+
+	RCONPort     int      `json:"rcon_port" binding:"omitempty,min=1,max=65535"`
+	RCONPassword string   `json:"rcon_password"`
+	RCONCommands []string `json:"rcon_commands"`
+
+	Check HealthCheck `json:"check"`
+
+	ProbeIntervalSeconds int `json:"probe_interval_seconds" binding:"omitempty,min=5,max=86400"`
+	ProbeTimeoutSeconds  int `json:"probe_timeout_seconds" binding:"omitempty,min=1,max=60"`
+	ProbeRetries         int `json:"probe_retries" binding:"omitempty,min=1,max=10"`
+	RetryBackoffSeconds  int `json:"retry_backoff_seconds" binding:"omitempty,min=1,max=300"`
 }
 
 // UpdateServerRequest represents the request to update a server
 type UpdateServerRequest struct {
 	Name        string `json:"name" binding:"required"`
-	Type        string `json:"type" binding:"required,oneof=minecraft cs2"`
+	Type        string `json:"type" binding:"required"` // A registered server type, see GET /api/server-types
 	Address     string `json:"address" binding:"required"`
-	Port        int    `json:"port" binding:"required,min=1,max=65535"`
+	Port        int    `json:"port" binding:"omitempty,min=1,max=65535"` // Optional for types with SRV lookup
+	QueryPort   int    `json:"query_port" binding:"omitempty,min=1,max=65535"`
 	Description string `json:"description"`
 	DownloadURL string `json:"download_url"`
 	Changelog   string `json:"changelog"`
+	Group       string `json:"group"`
+	EnableQuery bool   `json:"enable_query"`
+	HidePlayers bool   `json:"hide_players"`
+	DualStack   bool   `json:"dual_stack"`
+
+	RCONPort     int      `json:"rcon_port" binding:"omitempty,min=1,max=65535"`
+	RCONPassword string   `json:"rcon_password"` // Empty keeps the current password
+	RCONCommands []string `json:"rcon_commands"`
+
+	Check HealthCheck `json:"check"`
+
+	ProbeIntervalSeconds int `json:"probe_interval_seconds" binding:"omitempty,min=5,max=86400"`
+	ProbeTimeoutSeconds  int `json:"probe_timeout_seconds" binding:"omitempty,min=1,max=60"`
+	ProbeRetries         int `json:"probe_retries" binding:"omitempty,min=1,max=10"`
+	RetryBackoffSeconds  int `json:"retry_backoff_seconds" binding:"omitempty,min=1,max=300"`
 }
 
 // LoginRequest represents the login request
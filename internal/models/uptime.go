@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// Incident is a continuous period during which a server was offline
+type Incident struct {
+	Start    time.Time  `json:"start"`
+	End      *time.Time `json:"end,omitempty"` // Not set while the incident is ongoing
+	Duration int64      `json:"duration"`      // Offline time (seconds)
+	Ongoing  bool       `json:"ongoing"`       // Still offline at the end of the window
+}
+
+// UptimeReport describes the availability of a server over a time window
+type UptimeReport struct {
+	ServerID         uint       `json:"server_id"`
+	From             time.Time  `json:"from"`
+	To               time.Time  `json:"to"`
+	UptimePercent    *float64   `json:"uptime_percent"`    // Not set when there is no data for the window
+	MonitoredSeconds int64      `json:"monitored_seconds"` // Time covered by probes since the server was created
+	DowntimeSeconds  int64      `json:"downtime_seconds"`
+	Resolution       string     `json:"resolution"` // Storage tier the report was primarily computed from
+	IncidentCount    int        `json:"incident_count"`
+	Incidents        []Incident `json:"incidents"`
+}
+
+// UptimeSummary holds uptime percentages over the standard windows.
+// A window is not set when there is no data for it.
+type UptimeSummary struct {
+	Day     *float64 `json:"24h"`
+	Week    *float64 `json:"7d"`
+	Month   *float64 `json:"30d"`
+	Quarter *float64 `json:"90d"`
+}
@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEvent() events.Event {
+	return events.Event{
+		Type:      events.TypeWentOffline,
+		Server:    models.Server{ID: 1, Name: "Survival", Type: "minecraft", Address: "mc.example.com", Port: 25565, Group: "survival"},
+		Previous:  &models.ServerStatus{Online: true, Players: 12, MaxPlayers: 50, Version: "1.20.1", Ping: 40},
+		Current:   &models.ServerStatus{Online: false, Version: "Unknown"},
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Message:   "Survival went offline",
+	}
+}
+
+// captureServer starts a local HTTP stand-in that decodes the posted JSON into v
+func captureServer(t *testing.T, status int, v interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+			t.Errorf("Invalid JSON body: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+}
+
+func TestRenderMessage(t *testing.T) {
+	data := NewMessageData(testEvent())
+
+	message, err := renderMessage(&models.NotificationChannel{}, data)
+	if err != nil || message != "Survival went offline" {
+		t.Errorf("Expected default message, got %q (%v)", message, err)
+	}
+
+	channel := &models.NotificationChannel{
+		Template: "{{.Server.Name}} ({{.Address}}) is {{.Status}}, was {{.Previous.Players}} players",
+	}
+	message, err = renderMessage(channel, data)
+	if err != nil {
+		t.Fatal("Failed to render template:", err)
+	}
+	if message != "Survival (mc.example.com:25565) is offline, was 12 players" {
+		t.Errorf("Unexpected rendered message %q", message)
+	}
+
+	channel.Template = "{{.Missing}}"
+	if _, err := renderMessage(channel, data); err == nil {
+		t.Error("Expected error for unknown template field")
+	}
+}
+
+func TestMatches_ServerGroups(t *testing.T) {
+	event := testEvent()
+
+	if !Matches(&models.NotificationChannel{ServerGroups: []string{"survival"}}, event) {
+		t.Error("Expected channel for the server group to match")
+	}
+	if Matches(&models.NotificationChannel{ServerGroups: []string{"creative"}}, event) {
+		t.Error("Expected channel for another group not to match")
+	}
+	if !Matches(&models.NotificationChannel{ServerIDs: []uint{1}, ServerGroups: []string{"creative"}}, event) {
+		t.Error("Expected channel listing the server ID to match")
+	}
+}
+
+func TestDiscordSender(t *testing.T) {
+	var payload discordPayload
+	receiver := captureServer(t, http.StatusNoContent, &payload)
+	defer receiver.Close()
+
+	channel := &models.NotificationChannel{Type: models.ChannelTypeDiscord, URL: receiver.URL}
+	code, err := NewDiscordSender().Send(context.Background(), channel, testEvent())
+	if err != nil || code != http.StatusNoContent {
+		t.Fatalf("Send failed: %d %v", code, err)
+	}
+
+	if len(payload.Embeds) != 1 {
+		t.Fatalf("Expected 1 embed, got %d", len(payload.Embeds))
+	}
+	embed := payload.Embeds[0]
+	if embed.Title != "Survival" || embed.Description != "Survival went offline" || embed.Color != ColorOffline {
+		t.Errorf("Unexpected embed: %+v", embed)
+	}
+	if embed.Timestamp != "2024-05-01T12:00:00Z" {
+		t.Errorf("Unexpected timestamp %q", embed.Timestamp)
+	}
+
+	fields := make(map[string]string)
+	for _, f := range embed.Fields {
+		fields[f.Name] = f.Value
+	}
+	if fields["Status"] != "offline" || fields["Address"] != "mc.example.com:25565" || fields["Players"] != "-" {
+		t.Errorf("Unexpected fields: %v", fields)
+	}
+}
+
+func TestSlackSender(t *testing.T) {
+	var payload slackPayload
+	receiver := captureServer(t, http.StatusOK, &payload)
+	defer receiver.Close()
+
+	event := testEvent()
+	event.Type = events.TypeWentOnline
+	event.Previous, event.Current = event.Current, &models.ServerStatus{Online: true, Players: 3, MaxPlayers: 50, Version: "1.20.1", Ping: 35}
+
+	channel := &models.NotificationChannel{
+		Type:     models.ChannelTypeSlack,
+		URL:      receiver.URL,
+		Template: "{{.Server.Name}} is up with {{.Players}} players",
+	}
+	if _, err := NewSlackSender().Send(context.Background(), channel, event); err != nil {
+		t.Fatal("Send failed:", err)
+	}
+
+	if payload.Text != "Survival is up with 3/50 players" {
+		t.Errorf("Unexpected fallback text %q", payload.Text)
+	}
+	if len(payload.Attachments) != 1 || payload.Attachments[0].Color != "#2ecc71" {
+		t.Fatalf("Unexpected attachments: %+v", payload.Attachments)
+	}
+
+	blocks := payload.Attachments[0].Blocks
+	if len(blocks) != 3 || blocks[1].Type != "section" || len(blocks[1].Fields) != 6 {
+		t.Fatalf("Unexpected blocks: %+v", blocks)
+	}
+	if !strings.Contains(blocks[1].Fields[5].Text, "35ms") {
+		t.Errorf("Expected ping field, got %q", blocks[1].Fields[5].Text)
+	}
+}
+
+func TestChatSender_ErrorStatus(t *testing.T) {
+	var payload slackPayload
+	receiver := captureServer(t, http.StatusTooManyRequests, &payload)
+	defer receiver.Close()
+
+	channel := &models.NotificationChannel{Type: models.ChannelTypeSlack, URL: receiver.URL}
+	code, err := NewSlackSender().Send(context.Background(), channel, testEvent())
+	if err == nil || code != http.StatusTooManyRequests {
+		t.Errorf("Expected error for 429 response, got %d %v", code, err)
+	}
+}
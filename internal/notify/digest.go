@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"context"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"sync"
+	"time"
+)
+
+// DigestEventType marks digest deliveries in the delivery log
+const DigestEventType events.Type = "digest"
+
+// MaxDigestEvents is the number of events listed in a single digest; further
+// events are only counted
+const MaxDigestEvents = 500
+
+// Digest summarizes the events of a channel over one digest period
+type Digest struct {
+	ChannelID uint
+	Period    string // "hourly" or "daily"
+	From      time.Time
+	To        time.Time
+	Events    []events.Event
+	Dropped   int // Events not listed because the digest was full
+}
+
+// DigestSender is implemented by senders that can deliver digests
+type DigestSender interface {
+	SendDigest(ctx context.Context, channel *models.NotificationChannel, digest *Digest) (int, error)
+}
+
+// digestBuffer collects events of digest channels in memory until their
+// period ends. Buffered events are lost on restart.
+type digestBuffer struct {
+	pending map[uint]*Digest
+	mutex   sync.Mutex
+}
+
+// newDigestBuffer creates an empty digest buffer
+func newDigestBuffer() *digestBuffer {
+	return &digestBuffer{
+		pending: make(map[uint]*Digest),
+	}
+}
+
+// add buffers an event for a channel. A new digest starts at the beginning
+// of the channel's current period; the pending digest it replaces, whose
+// period ended or was changed, is returned so that it can be sent first.
+func (b *digestBuffer) add(channel *models.NotificationChannel, event events.Event, now time.Time) (ended *Digest) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	digest, exists := b.pending[channel.ID]
+	if exists && (digest.Period != channel.Digest || !now.Before(digest.To)) {
+		ended = digest
+		exists = false
+	}
+	if !exists {
+		from := periodStart(channel.Digest, now)
+		digest = &Digest{
+			ChannelID: channel.ID,
+			Period:    channel.Digest,
+			From:      from,
+			To:        periodEnd(channel.Digest, from),
+		}
+		b.pending[channel.ID] = digest
+	}
+
+	if len(digest.Events) >= MaxDigestEvents {
+		digest.Dropped++
+		return ended
+	}
+	digest.Events = append(digest.Events, event)
+	return ended
+}
+
+// takeDue removes and returns the digests whose period has ended
+func (b *digestBuffer) takeDue(now time.Time) []*Digest {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	var due []*Digest
+	for id, digest := range b.pending {
+		if !now.Before(digest.To) {
+			due = append(due, digest)
+			delete(b.pending, id)
+		}
+	}
+	return due
+}
+
+// periodStart returns the start of the digest period containing t. Periods
+// are aligned to UTC hours and days.
+func periodStart(period string, t time.Time) time.Time {
+	t = t.UTC()
+	if period == models.DigestDaily {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+// periodEnd returns the end of the digest period starting at start
+func periodEnd(period string, start time.Time) time.Time {
+	if period == models.DigestDaily {
+		return start.AddDate(0, 0, 1)
+	}
+	return start.Add(time.Hour)
+}
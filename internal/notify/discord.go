@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"net/http"
+	"time"
+)
+
+// discordPayload is the body of a Discord webhook execution
+type discordPayload struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+	Footer      *discordFooter `json:"footer,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+// DiscordSender posts events to Discord webhooks as embeds
+type DiscordSender struct {
+	client *http.Client
+}
+
+// NewDiscordSender creates a new Discord sender
+func NewDiscordSender() *DiscordSender {
+	return &DiscordSender{
+		client: &http.Client{},
+	}
+}
+
+// Send posts the event to the channel's Discord webhook URL
+func (s *DiscordSender) Send(ctx context.Context, channel *models.NotificationChannel, event events.Event) (int, error) {
+	data := NewMessageData(event)
+	message, err := renderMessage(channel, data)
+	if err != nil {
+		return 0, err
+	}
+
+	embed := discordEmbed{
+		Title:       data.Server.Name,
+		Description: message,
+		Color:       data.Color(),
+		Fields: []discordField{
+			{Name: "Status", Value: data.Status(), Inline: true},
+			{Name: "Type", Value: data.Server.Type, Inline: true},
+			{Name: "Address", Value: data.Address(), Inline: true},
+			{Name: "Players", Value: data.Players(), Inline: true},
+			{Name: "Version", Value: data.Version(), Inline: true},
+			{Name: "Ping", Value: data.Ping(), Inline: true},
+		},
+		Footer: &discordFooter{Text: "Game Server Monitor · " + string(data.Event)},
+	}
+	if !data.Timestamp.IsZero() {
+		embed.Timestamp = data.Timestamp.UTC().Format(time.RFC3339)
+	}
+
+	body, err := json.Marshal(discordPayload{
+		Username: "Game Server Monitor",
+		Embeds:   []discordEmbed{embed},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode discord payload: %w", err)
+	}
+
+	return postJSON(ctx, s.client, channel.URL, body, nil)
+}
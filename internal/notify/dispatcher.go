@@ -0,0 +1,426 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"log"
+	"sync"
+	"time"
+)
+
+// TestEventType marks events sent by the channel test endpoint
+const TestEventType events.Type = "test"
+
+// Sender delivers events to one type of notification channel
+type Sender interface {
+	// Send delivers the event and returns the HTTP status code of the
+	// response, or 0 when there was none
+	Send(ctx context.Context, channel *models.NotificationChannel, event events.Event) (int, error)
+}
+
+// DispatcherConfig holds configuration for notification delivery
+type DispatcherConfig struct {
+	Workers        int
+	QueueSize      int
+	MaxAttempts    int
+	RetryBackoff   time.Duration // Delay before the first retry, doubled for every further retry
+	Timeout        time.Duration // Timeout of a single delivery attempt
+	DigestInterval time.Duration // How often ended digest periods are checked, 0 to only flush manually
+}
+
+// DefaultDispatcherConfig returns default configuration
+func DefaultDispatcherConfig() *DispatcherConfig {
+	return &DispatcherConfig{
+		Workers:        4,
+		QueueSize:      256,
+		MaxAttempts:    4,               // 1 attempt + 3 retries
+		RetryBackoff:   2 * time.Second, // Retry after 2s, 4s, 8s
+		Timeout:        10 * time.Second,
+		DigestInterval: time.Minute,
+	}
+}
+
+// emailDefaultEvents are the events email channels without an event filter
+// send immediately
+var emailDefaultEvents = []string{
+	string(events.TypeWentOffline),
+	string(events.TypeWentOnline),
+	string(events.TypeFlapStart),
+	string(events.TypeFlapEnd),
+}
+
+// delivery is a queued notification of one event, or one digest, to one channel
+type delivery struct {
+	channel      models.NotificationChannel
+	event        events.Event
+	digest       *Digest
+	attempts     int
+	responseCode int
+	err          error
+}
+
+// Dispatcher turns server state-change events into notifications. Deliveries
+// go through a retry queue and their outcome is recorded in the delivery log.
+type Dispatcher struct {
+	dbService   *database.DatabaseService
+	config      *DispatcherConfig
+	senders     map[string]Sender
+	digests     *digestBuffer
+	queue       chan *delivery
+	ctx         context.Context
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	running     bool
+	unsubscribe func()
+	mutex       sync.RWMutex
+}
+
+// NewDispatcher creates a new notification dispatcher
+func NewDispatcher(dbService *database.DatabaseService, config *DispatcherConfig) *Dispatcher {
+	if config == nil {
+		config = DefaultDispatcherConfig()
+	}
+
+	d := &Dispatcher{
+		dbService: dbService,
+		config:    config,
+		senders:   make(map[string]Sender),
+		digests:   newDigestBuffer(),
+	}
+
+	d.RegisterSender(models.ChannelTypeWebhook, NewWebhookSender())
+	d.RegisterSender(models.ChannelTypeDiscord, NewDiscordSender())
+	d.RegisterSender(models.ChannelTypeSlack, NewSlackSender())
+	d.RegisterSender(models.ChannelTypeEmail, NewEmailSender())
+
+	return d
+}
+
+// RegisterSender sets the sender used for a channel type
+func (d *Dispatcher) RegisterSender(channelType string, sender Sender) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.senders[channelType] = sender
+}
+
+// Start subscribes to the event bus and starts the delivery workers
+func (d *Dispatcher) Start(bus *events.Bus) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.running {
+		return nil // Already running
+	}
+
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	d.queue = make(chan *delivery, d.config.QueueSize)
+	d.running = true
+
+	for i := 0; i < d.config.Workers; i++ {
+		d.wg.Add(1)
+		go d.worker()
+	}
+
+	d.wg.Add(1)
+	go d.digestLoop()
+
+	d.unsubscribe = bus.Subscribe("notifications", d.HandleEvent)
+
+	log.Printf("Notification dispatcher started with %d workers", d.config.Workers)
+	return nil
+}
+
+// Stop unsubscribes from the event bus and stops the delivery workers.
+// Deliveries still queued or waiting for a retry and unsent digests are dropped.
+func (d *Dispatcher) Stop() error {
+	d.mutex.Lock()
+	if !d.running {
+		d.mutex.Unlock()
+		return nil // Already stopped
+	}
+	d.running = false
+	unsubscribe := d.unsubscribe
+	d.mutex.Unlock()
+
+	unsubscribe()
+	d.cancel()
+	d.wg.Wait()
+
+	log.Println("Notification dispatcher stopped")
+	return nil
+}
+
+// HandleEvent queues a delivery of the event to every matching enabled
+// channel, unless it is suppressed, and adds it to the pending digests of
+// digest channels
+func (d *Dispatcher) HandleEvent(event events.Event) {
+	// Servers in planned maintenance are muted, digests included
+	if event.Current != nil && event.Current.Maintenance {
+		return
+	}
+
+	channels, err := d.dbService.GetEnabledNotificationChannels()
+	if err != nil {
+		log.Printf("Failed to load notification channels: %v", err)
+		return
+	}
+
+	for _, channel := range channels {
+		if !matchesServer(&channel, event) {
+			continue
+		}
+
+		// Digests summarize all transitions of the channel's servers
+		if channel.Digest != "" {
+			if ended := d.digests.add(&channel, event, time.Now()); ended != nil {
+				d.enqueue(&delivery{channel: channel, digest: ended})
+			}
+		}
+
+		if Suppressed(event) {
+			continue
+		}
+
+		if matchesEvent(&channel, event) {
+			d.enqueue(&delivery{channel: channel, event: event})
+		}
+	}
+}
+
+// FlushDigests queues the digests whose period ended before now
+func (d *Dispatcher) FlushDigests(now time.Time) {
+	for _, digest := range d.digests.takeDue(now) {
+		// Use the current channel settings, the channel may have changed
+		channel, err := d.dbService.GetNotificationChannel(digest.ChannelID)
+		if err != nil || !channel.Enabled || channel.Digest == "" {
+			continue
+		}
+		d.enqueue(&delivery{channel: *channel, digest: digest})
+	}
+}
+
+// SendTest synchronously sends a test event to a channel without retries
+func (d *Dispatcher) SendTest(channel *models.NotificationChannel) (int, error) {
+	event := events.Event{
+		Type: TestEventType,
+		Server: models.Server{
+			Name:    "Test Server",
+			Group:   "test",
+			Type:    "minecraft",
+			Address: "play.example.com",
+			Port:    25565,
+		},
+		Previous:  &models.ServerStatus{Online: false, Version: "Unknown", LastUpdated: time.Now()},
+		Current:   &models.ServerStatus{Online: true, Players: 3, MaxPlayers: 20, Version: "1.20.1", Ping: 42, LastUpdated: time.Now()},
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("Test notification for channel %s", channel.Name),
+	}
+
+	sender, err := d.sender(channel.Type)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.Timeout)
+	defer cancel()
+
+	return sender.Send(ctx, channel, event)
+}
+
+// Suppressed reports whether immediate notifications of an event are
+// suppressed because its server is in maintenance or flapping. The flap_start
+// and flap_end events summarize the flapping period instead.
+func Suppressed(event events.Event) bool {
+	if event.Current != nil && event.Current.Maintenance {
+		return true
+	}
+	if event.Type == events.TypeFlapStart || event.Type == events.TypeFlapEnd {
+		return false
+	}
+	return event.Current != nil && event.Current.Flapping
+}
+
+// Matches reports whether a channel is interested in an event
+func Matches(channel *models.NotificationChannel, event events.Event) bool {
+	return matchesEvent(channel, event) && matchesServer(channel, event)
+}
+
+// matchesEvent reports whether a channel wants the event type. Email channels
+// without an event filter only want outages and recoveries.
+func matchesEvent(channel *models.NotificationChannel, event events.Event) bool {
+	types := channel.Events
+	if len(types) == 0 && channel.Type == models.ChannelTypeEmail {
+		types = emailDefaultEvents
+	}
+	return len(types) == 0 || containsString(types, string(event.Type))
+}
+
+// matchesServer reports whether a channel wants events of the server. A
+// channel limited to servers and/or server groups matches a server in either.
+func matchesServer(channel *models.NotificationChannel, event events.Event) bool {
+	if len(channel.ServerIDs) == 0 && len(channel.ServerGroups) == 0 {
+		return true
+	}
+
+	for _, id := range channel.ServerIDs {
+		if id == event.Server.ID {
+			return true
+		}
+	}
+
+	return event.Server.Group != "" && containsString(channel.ServerGroups, event.Server.Group)
+}
+
+// enqueue adds a delivery to the queue, dropping it when the queue is full
+func (d *Dispatcher) enqueue(job *delivery) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if !d.running {
+		return
+	}
+
+	select {
+	case d.queue <- job:
+	default:
+		log.Printf("Notification queue is full, dropping notification to channel %s", job.channel.Name)
+	}
+}
+
+// worker performs queued deliveries until the dispatcher stops
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case job := <-d.queue:
+			d.attempt(job)
+		}
+	}
+}
+
+// digestLoop periodically queues digests whose period has ended
+func (d *Dispatcher) digestLoop() {
+	defer d.wg.Done()
+
+	if d.config.DigestInterval <= 0 {
+		return // Digests are flushed manually
+	}
+
+	ticker := time.NewTicker(d.config.DigestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case now := <-ticker.C:
+			d.FlushDigests(now)
+		}
+	}
+}
+
+// attempt performs one delivery attempt and schedules a retry on failure
+func (d *Dispatcher) attempt(job *delivery) {
+	job.attempts++
+
+	sender, err := d.sender(job.channel.Type)
+	if err != nil {
+		job.err = err
+		d.record(job)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, d.config.Timeout)
+	if job.digest != nil {
+		if digestSender, ok := sender.(DigestSender); ok {
+			job.responseCode, job.err = digestSender.SendDigest(ctx, &job.channel, job.digest)
+		} else {
+			job.responseCode, job.err = 0, errors.New("digests are not supported by "+job.channel.Type+" channels")
+		}
+	} else {
+		job.responseCode, job.err = sender.Send(ctx, &job.channel, job.event)
+	}
+	cancel()
+
+	if job.err == nil || job.attempts >= d.config.MaxAttempts {
+		d.record(job)
+		return
+	}
+
+	log.Printf("Notification attempt %d to channel %s failed: %v", job.attempts, job.channel.Name, job.err)
+	d.scheduleRetry(job)
+}
+
+// scheduleRetry requeues a delivery after an exponential backoff
+func (d *Dispatcher) scheduleRetry(job *delivery) {
+	backoff := d.config.RetryBackoff << (job.attempts - 1)
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+
+		timer := time.NewTimer(backoff)
+		defer timer.Stop()
+
+		select {
+		case <-d.ctx.Done():
+		case <-timer.C:
+			d.enqueue(job)
+		}
+	}()
+}
+
+// record stores the final outcome of a delivery in the delivery log
+func (d *Dispatcher) record(job *delivery) {
+	entry := &models.NotificationDelivery{
+		ChannelID:    job.channel.ID,
+		ServerID:     job.event.Server.ID,
+		EventType:    string(job.event.Type),
+		Status:       models.DeliveryStatusSuccess,
+		Attempts:     job.attempts,
+		ResponseCode: job.responseCode,
+	}
+	if job.digest != nil {
+		entry.ServerID = 0
+		entry.EventType = string(DigestEventType)
+	}
+
+	if job.err != nil {
+		entry.Status = models.DeliveryStatusFailed
+		entry.Error = job.err.Error()
+		log.Printf("Notification to channel %s failed after %d attempts: %v", job.channel.Name, job.attempts, job.err)
+	}
+
+	if err := d.dbService.RecordNotificationDelivery(entry); err != nil {
+		log.Printf("Failed to record notification delivery: %v", err)
+	}
+}
+
+// sender returns the sender for a channel type
+func (d *Dispatcher) sender(channelType string) (Sender, error) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	sender, exists := d.senders[channelType]
+	if !exists {
+		return nil, errors.New("unsupported channel type: " + channelType)
+	}
+	return sender, nil
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
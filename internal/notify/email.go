@@ -0,0 +1,180 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"game-server-monitor/internal/secrets"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	emailSubjectPrefix = "[Game Server Monitor] "
+	emailTimeFormat    = "2006-01-02 15:04:05 MST"
+)
+
+// EmailSender delivers events and digests by SMTP
+type EmailSender struct{}
+
+// NewEmailSender creates a new email sender
+func NewEmailSender() *EmailSender {
+	return &EmailSender{}
+}
+
+// Send emails a single event to the channel recipients
+func (s *EmailSender) Send(ctx context.Context, channel *models.NotificationChannel, event events.Event) (int, error) {
+	data := NewMessageData(event)
+	message, err := renderMessage(channel, data)
+	if err != nil {
+		return 0, err
+	}
+
+	// The first line of the message doubles as the subject
+	subject := strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+	if subject == "" {
+		subject = data.Server.Name + " is " + data.Status()
+	}
+
+	var body strings.Builder
+	body.WriteString(message)
+	body.WriteString("\n\n")
+	fmt.Fprintf(&body, "Server:   %s (%s)\n", data.Server.Name, data.Server.Type)
+	fmt.Fprintf(&body, "Address:  %s\n", data.Address())
+	fmt.Fprintf(&body, "Status:   %s\n", data.Status())
+	fmt.Fprintf(&body, "Players:  %s\n", data.Players())
+	fmt.Fprintf(&body, "Version:  %s\n", data.Version())
+	fmt.Fprintf(&body, "Ping:     %s\n", data.Ping())
+	fmt.Fprintf(&body, "Time:     %s\n", data.Timestamp.UTC().Format(emailTimeFormat))
+
+	return sendMail(ctx, channel, emailSubjectPrefix+subject, body.String())
+}
+
+// SendDigest emails a summary of all buffered events to the channel recipients
+func (s *EmailSender) SendDigest(ctx context.Context, channel *models.NotificationChannel, digest *Digest) (int, error) {
+	period := "Hourly"
+	if digest.Period == models.DigestDaily {
+		period = "Daily"
+	}
+
+	subject := fmt.Sprintf("%s digest: %d status changes", period, len(digest.Events))
+	if len(digest.Events) == 1 {
+		subject = fmt.Sprintf("%s digest: 1 status change", period)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "%d server status changes between %s and %s.\n\n",
+		len(digest.Events)+digest.Dropped,
+		digest.From.UTC().Format(emailTimeFormat), digest.To.UTC().Format(emailTimeFormat))
+
+	for _, event := range digest.Events {
+		fmt.Fprintf(&body, "%s  %s\n", event.Timestamp.UTC().Format(emailTimeFormat), event.Message)
+	}
+	if digest.Dropped > 0 {
+		fmt.Fprintf(&body, "\n%d more changes were not listed.\n", digest.Dropped)
+	}
+
+	return sendMail(ctx, channel, emailSubjectPrefix+subject, body.String())
+}
+
+// sendMail sends a plain text email to the channel recipients. It returns the
+// last SMTP reply code.
+func sendMail(ctx context.Context, channel *models.NotificationChannel, subject, body string) (int, error) {
+	addr := net.JoinHostPort(channel.SMTPHost, strconv.Itoa(channel.SMTPPort))
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, channel.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return replyCode(err), fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if channel.SMTPStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return 0, errors.New("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: channel.SMTPHost}); err != nil {
+			return replyCode(err), fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+
+	if channel.SMTPUsername != "" {
+		password, err := secrets.Decrypt(channel.SMTPPassword)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decrypt SMTP password: %w", err)
+		}
+		auth := smtp.PlainAuth("", channel.SMTPUsername, password, channel.SMTPHost)
+		if err := client.Auth(auth); err != nil {
+			return replyCode(err), fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(channel.EmailFrom); err != nil {
+		return replyCode(err), fmt.Errorf("MAIL FROM rejected: %w", err)
+	}
+	for _, recipient := range channel.EmailTo {
+		if err := client.Rcpt(recipient); err != nil {
+			return replyCode(err), fmt.Errorf("RCPT TO %s rejected: %w", recipient, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return replyCode(err), fmt.Errorf("DATA rejected: %w", err)
+	}
+	if _, err := writer.Write(buildMessage(channel.EmailFrom, channel.EmailTo, subject, body)); err != nil {
+		return 0, fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return replyCode(err), fmt.Errorf("message rejected: %w", err)
+	}
+
+	client.Quit()
+	return 250, nil
+}
+
+// buildMessage builds a quoted-printable encoded plain text email
+func buildMessage(from string, to []string, subject, body string) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	writer := quotedprintable.NewWriter(&msg)
+	writer.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n")))
+	writer.Close()
+
+	return msg.Bytes()
+}
+
+// replyCode extracts the SMTP reply code from an error, or 0 if it has none
+func replyCode(err error) int {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code
+	}
+	return 0
+}
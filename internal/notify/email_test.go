@@ -0,0 +1,279 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"game-server-monitor/internal/secrets"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer is a minimal local SMTP stand-in that records received mail
+type fakeSMTPServer struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	auth     []string
+	from     []string
+	to       [][]string
+	messages []string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Failed to listen:", err)
+	}
+
+	s := &fakeSMTPServer{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	var from string
+	var to []string
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch command {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			parts := strings.Fields(line)
+			decoded, _ := base64.StdEncoding.DecodeString(parts[len(parts)-1])
+			s.mutex.Lock()
+			s.auth = append(s.auth, string(decoded))
+			s.mutex.Unlock()
+			reply("235 Authentication successful")
+		case "MAIL":
+			from = line
+			to = nil
+			reply("250 OK")
+		case "RCPT":
+			to = append(to, line)
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.mutex.Lock()
+			s.from = append(s.from, from)
+			s.to = append(s.to, to)
+			s.messages = append(s.messages, data.String())
+			s.mutex.Unlock()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) channel() *models.NotificationChannel {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	password, err := secrets.Encrypt("hunter2")
+	if err != nil {
+		panic(err)
+	}
+	return &models.NotificationChannel{
+		Type:         models.ChannelTypeEmail,
+		SMTPHost:     "127.0.0.1",
+		SMTPPort:     addr.Port,
+		SMTPUsername: "monitor",
+		SMTPPassword: password,
+		EmailFrom:    "monitor@example.com",
+		EmailTo:      []string{"ops@example.com", "oncall@example.com"},
+	}
+}
+
+// readMessage parses a received message and decodes its body
+func readMessage(t *testing.T, raw string) (*mail.Message, string) {
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal("Invalid message:", err)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatal("Invalid message body:", err)
+	}
+	return msg, string(body)
+}
+
+func TestEmailSender_Send(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	defer server.listener.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	code, err := NewEmailSender().Send(ctx, server.channel(), testEvent())
+	if err != nil || code != 250 {
+		t.Fatalf("Send failed: %d %v", code, err)
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if len(server.messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(server.messages))
+	}
+	if len(server.auth) != 1 || server.auth[0] != "\x00monitor\x00hunter2" {
+		t.Errorf("Unexpected auth %q", server.auth)
+	}
+	if !strings.Contains(server.from[0], "<monitor@example.com>") || len(server.to[0]) != 2 {
+		t.Errorf("Unexpected envelope: %s %v", server.from[0], server.to[0])
+	}
+
+	msg, body := readMessage(t, server.messages[0])
+	if subject := msg.Header.Get("Subject"); subject != "[Game Server Monitor] Survival went offline" {
+		t.Errorf("Unexpected subject %q", subject)
+	}
+	if !strings.Contains(body, "Address:  mc.example.com:25565") || !strings.Contains(body, "Status:   offline") {
+		t.Errorf("Unexpected body:\n%s", body)
+	}
+}
+
+func TestEmailSender_SendDigest(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	defer server.listener.Close()
+
+	first := testEvent()
+	second := testEvent()
+	second.Type = events.TypeWentOnline
+	second.Message = "Survival is back online"
+	second.Timestamp = first.Timestamp.Add(10 * time.Minute)
+
+	digest := &Digest{
+		Period: models.DigestHourly,
+		From:   first.Timestamp.Truncate(time.Hour),
+		To:     first.Timestamp.Truncate(time.Hour).Add(time.Hour),
+		Events: []events.Event{first, second},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := NewEmailSender().SendDigest(ctx, server.channel(), digest); err != nil {
+		t.Fatal("SendDigest failed:", err)
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	msg, body := readMessage(t, server.messages[0])
+	if subject := msg.Header.Get("Subject"); subject != "[Game Server Monitor] Hourly digest: 2 status changes" {
+		t.Errorf("Unexpected subject %q", subject)
+	}
+	if !strings.Contains(body, "2024-05-01 12:00:00 UTC  Survival went offline") ||
+		!strings.Contains(body, "2024-05-01 12:10:00 UTC  Survival is back online") {
+		t.Errorf("Unexpected body:\n%s", body)
+	}
+}
+
+func TestDigestBuffer(t *testing.T) {
+	buffer := newDigestBuffer()
+	hourly := &models.NotificationChannel{ID: 1, Digest: models.DigestHourly}
+	daily := &models.NotificationChannel{ID: 2, Digest: models.DigestDaily}
+
+	now := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	buffer.add(hourly, testEvent(), now)
+	buffer.add(hourly, testEvent(), now.Add(10*time.Minute))
+	buffer.add(daily, testEvent(), now)
+
+	if due := buffer.takeDue(now.Add(20 * time.Minute)); len(due) != 0 {
+		t.Fatalf("Expected no due digests, got %d", len(due))
+	}
+
+	due := buffer.takeDue(time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC))
+	if len(due) != 1 || due[0].ChannelID != 1 || len(due[0].Events) != 2 {
+		t.Fatalf("Expected the hourly digest with 2 events, got %+v", due)
+	}
+	if !due[0].From.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected digest start %v", due[0].From)
+	}
+
+	due = buffer.takeDue(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC))
+	if len(due) != 1 || due[0].ChannelID != 2 {
+		t.Fatalf("Expected the daily digest, got %+v", due)
+	}
+}
+
+func TestDigestBuffer_EventAfterPeriod(t *testing.T) {
+	buffer := newDigestBuffer()
+	hourly := &models.NotificationChannel{ID: 1, Digest: models.DigestHourly}
+
+	now := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	if ended := buffer.add(hourly, testEvent(), now); ended != nil {
+		t.Fatalf("Expected no ended digest, got %+v", ended)
+	}
+
+	// The period ended but the digest was not flushed yet
+	later := time.Date(2024, 5, 1, 13, 5, 0, 0, time.UTC)
+	ended := buffer.add(hourly, testEvent(), later)
+	if ended == nil || len(ended.Events) != 1 || !ended.To.Equal(time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected the ended digest with 1 event, got %+v", ended)
+	}
+
+	due := buffer.takeDue(time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC))
+	if len(due) != 1 || len(due[0].Events) != 1 {
+		t.Fatalf("Expected the new digest with 1 event, got %+v", due)
+	}
+	if !due[0].From.Equal(time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected digest start %v", due[0].From)
+	}
+}
+
+func TestMatches_EmailDefaults(t *testing.T) {
+	channel := &models.NotificationChannel{Type: models.ChannelTypeEmail}
+
+	event := testEvent()
+	if !Matches(channel, event) {
+		t.Error("Expected email channel to match outages by default")
+	}
+
+	event.Type = events.TypeVersionChanged
+	if Matches(channel, event) {
+		t.Error("Expected email channel not to match version changes by default")
+	}
+}
@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"net"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+// Status colors used by chat channels
+const (
+	ColorOnline   = 0x2ECC71 // Green
+	ColorOffline  = 0xE74C3C // Red
+	ColorDegraded = 0xE67E22 // Orange
+	ColorUnknown  = 0x95A5A6 // Grey
+)
+
+// MessageData is the data passed to channel message templates, e.g.
+// "{{.Server.Name}} is {{.Status}} ({{.Players}} players)"
+type MessageData struct {
+	Event     events.Type
+	Message   string // Default message of the event
+	Server    models.Server
+	Previous  *models.ServerStatus
+	Current   *models.ServerStatus
+	Timestamp time.Time
+	Threshold float64
+	Direction string
+	Rule      *models.AlertRule // Set for alert events
+	Value     float64           // Metric value of alert events
+}
+
+// NewMessageData builds the template data for an event
+func NewMessageData(event events.Event) MessageData {
+	return MessageData{
+		Event:     event.Type,
+		Message:   event.Message,
+		Server:    event.Server,
+		Previous:  event.Previous,
+		Current:   event.Current,
+		Timestamp: event.Timestamp,
+		Threshold: event.Threshold,
+		Direction: event.Direction,
+		Rule:      event.Rule,
+		Value:     event.Value,
+	}
+}
+
+// Address returns the server address as host:port, or just the host for
+// servers located via SRV records
+func (d MessageData) Address() string {
+	if d.Server.Port == 0 {
+		return d.Server.Address
+	}
+	return net.JoinHostPort(d.Server.Address, strconv.Itoa(d.Server.Port))
+}
+
+// Status returns "online", "offline" or "unknown"
+func (d MessageData) Status() string {
+	switch {
+	case d.Current == nil:
+		return "unknown"
+	case d.Current.Online:
+		return "online"
+	default:
+		return "offline"
+	}
+}
+
+// Players returns the player count as "players/max", or "-" when offline
+func (d MessageData) Players() string {
+	if d.Current == nil || !d.Current.Online {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", d.Current.Players, d.Current.MaxPlayers)
+}
+
+// Version returns the detected server version, or "-" when unknown
+func (d MessageData) Version() string {
+	if d.Current == nil || d.Current.Version == "" || d.Current.Version == "Unknown" {
+		return "-"
+	}
+	return d.Current.Version
+}
+
+// Ping returns the response time as "42ms", or "-" when offline
+func (d MessageData) Ping() string {
+	if d.Current == nil || !d.Current.Online {
+		return "-"
+	}
+	return fmt.Sprintf("%dms", d.Current.Ping)
+}
+
+// Color returns the status color of the event
+func (d MessageData) Color() int {
+	switch {
+	case d.Current == nil:
+		return ColorUnknown
+	case !d.Current.Online:
+		return ColorOffline
+	case d.Event == events.TypePingDegraded || d.Event == events.TypeAlertFiring || d.Event == events.TypeFlapStart:
+		return ColorDegraded
+	case d.Event == events.TypePlayerThresholdCrossed && d.Direction == events.DirectionUp:
+		return ColorDegraded
+	default:
+		return ColorOnline
+	}
+}
+
+// renderMessage returns the channel's message for an event, rendering the
+// channel template when one is set
+func renderMessage(channel *models.NotificationChannel, data MessageData) (string, error) {
+	if channel.Template == "" {
+		return data.Message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(channel.Template)
+	if err != nil {
+		return "", fmt.Errorf("invalid message template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render message template: %w", err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"net/http"
+	"time"
+)
+
+// slackPayload is the body of a Slack incoming webhook message. Blocks are
+// wrapped in an attachment so the message gets a colored status bar.
+type slackPayload struct {
+	Text        string            `json:"text"` // Fallback for notifications
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// SlackSender posts events to Slack incoming webhooks as Block Kit messages
+type SlackSender struct {
+	client *http.Client
+}
+
+// NewSlackSender creates a new Slack sender
+func NewSlackSender() *SlackSender {
+	return &SlackSender{
+		client: &http.Client{},
+	}
+}
+
+// Send posts the event to the channel's Slack webhook URL
+func (s *SlackSender) Send(ctx context.Context, channel *models.NotificationChannel, event events.Event) (int, error) {
+	data := NewMessageData(event)
+	message, err := renderMessage(channel, data)
+	if err != nil {
+		return 0, err
+	}
+
+	field := func(name, value string) slackText {
+		return slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", name, value)}
+	}
+
+	footer := string(data.Event)
+	if !data.Timestamp.IsZero() {
+		footer += " · " + data.Timestamp.UTC().Format(time.RFC3339)
+	}
+
+	body, err := json.Marshal(slackPayload{
+		Text: message,
+		Attachments: []slackAttachment{{
+			Color: fmt.Sprintf("#%06x", data.Color()),
+			Blocks: []slackBlock{
+				{
+					Type: "section",
+					Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", data.Server.Name, message)},
+				},
+				{
+					Type: "section",
+					Fields: []slackText{
+						field("Status", data.Status()),
+						field("Type", data.Server.Type),
+						field("Address", data.Address()),
+						field("Players", data.Players()),
+						field("Version", data.Version()),
+						field("Ping", data.Ping()),
+					},
+				},
+				{
+					Type:     "context",
+					Elements: []slackText{{Type: "mrkdwn", Text: footer}},
+				},
+			},
+		}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode slack payload: %w", err)
+	}
+
+	return postJSON(ctx, s.client, channel.URL, body, nil)
+}
@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"game-server-monitor/internal/secrets"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 signature of the request body
+	SignatureHeader = "X-Signature-256"
+	// EventHeader carries the event type of the notification
+	EventHeader = "X-Monitor-Event"
+)
+
+// WebhookPayload is the JSON body posted to generic webhooks
+type WebhookPayload struct {
+	Event     events.Type          `json:"event"`
+	Message   string               `json:"message"`
+	Timestamp time.Time            `json:"timestamp"`
+	Server    models.Server        `json:"server"`
+	OldStatus *models.ServerStatus `json:"old_status"`
+	NewStatus *models.ServerStatus `json:"new_status"`
+	Threshold float64              `json:"threshold,omitempty"`
+	Direction string               `json:"direction,omitempty"`
+	Rule      *models.AlertRule    `json:"rule,omitempty"`
+	Value     float64              `json:"value,omitempty"`
+}
+
+// NewWebhookPayload builds the webhook payload for an event
+func NewWebhookPayload(event events.Event) WebhookPayload {
+	return WebhookPayload{
+		Event:     event.Type,
+		Message:   event.Message,
+		Timestamp: event.Timestamp,
+		Server:    event.Server,
+		OldStatus: event.Previous,
+		NewStatus: event.Current,
+		Threshold: event.Threshold,
+		Direction: event.Direction,
+		Rule:      event.Rule,
+		Value:     event.Value,
+	}
+}
+
+// WebhookSender posts signed JSON payloads to generic webhook endpoints
+type WebhookSender struct {
+	client *http.Client
+}
+
+// NewWebhookSender creates a new webhook sender
+func NewWebhookSender() *WebhookSender {
+	return &WebhookSender{
+		client: &http.Client{},
+	}
+}
+
+// Send posts the event to the channel URL. When the channel has a secret the
+// body is signed with HMAC-SHA256 in the X-Signature-256 header.
+func (s *WebhookSender) Send(ctx context.Context, channel *models.NotificationChannel, event events.Event) (int, error) {
+	payload := NewWebhookPayload(event)
+	message, err := renderMessage(channel, NewMessageData(event))
+	if err != nil {
+		return 0, err
+	}
+	payload.Message = message
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	headers := map[string]string{
+		EventHeader: string(event.Type),
+	}
+	secret, err := secrets.Decrypt(channel.Secret)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+	if secret != "" {
+		headers[SignatureHeader] = Sign(secret, body)
+	}
+
+	return postJSON(ctx, s.client, channel.URL, body, headers)
+}
+
+// Sign returns the signature header value of a body: "sha256=" followed by
+// the hex encoded HMAC-SHA256 of the body keyed with the secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// postJSON posts a JSON body and treats non-2xx responses as errors
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "game-server-monitor")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drain a bounded amount of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,182 @@
+package notify
+
+import (
+	"encoding/json"
+	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256("secret", "hello")
+	expected := "sha256=88aab3ede8d3adf94d26ab90d3bafd4a2083070c3bcce9c014ee04a443847c0b"
+	if got := Sign("secret", []byte("hello")); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	event := events.Event{Type: events.TypeWentOffline, Server: models.Server{ID: 7}}
+
+	tests := []struct {
+		name    string
+		channel models.NotificationChannel
+		want    bool
+	}{
+		{"no filters", models.NotificationChannel{}, true},
+		{"event matches", models.NotificationChannel{Events: []string{"went_online", "went_offline"}}, true},
+		{"event filtered", models.NotificationChannel{Events: []string{"went_online"}}, false},
+		{"server matches", models.NotificationChannel{ServerIDs: []uint{3, 7}}, true},
+		{"server filtered", models.NotificationChannel{ServerIDs: []uint{3}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := Matches(&tt.channel, event); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSuppressed(t *testing.T) {
+	flapping := &models.ServerStatus{Online: false, Flapping: true}
+
+	if !Suppressed(events.Event{Type: events.TypeWentOffline, Current: flapping}) {
+		t.Error("Expected events of flapping servers to be suppressed")
+	}
+	if Suppressed(events.Event{Type: events.TypeFlapStart, Current: flapping}) {
+		t.Error("Expected flap_start not to be suppressed")
+	}
+	if !Suppressed(events.Event{Type: events.TypeWentOffline, Current: &models.ServerStatus{Maintenance: true}}) {
+		t.Error("Expected events of servers in maintenance to be suppressed")
+	}
+	if Suppressed(events.Event{Type: events.TypeWentOffline, Current: &models.ServerStatus{}}) {
+		t.Error("Expected events of stable servers not to be suppressed")
+	}
+}
+
+func TestDispatcher_WebhookDelivery(t *testing.T) {
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+	dbService := database.NewDatabaseService()
+
+	var mutex sync.Mutex
+	var received []WebhookPayload
+	done := make(chan struct{})
+	requests := 0
+
+	// Receiver fails the first request to exercise the retry queue
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(SignatureHeader) != Sign("top-secret", body) {
+			t.Errorf("Invalid signature header %q", r.Header.Get(SignatureHeader))
+		}
+		if r.Header.Get(EventHeader) != string(events.TypeWentOffline) {
+			t.Errorf("Unexpected event header %q", r.Header.Get(EventHeader))
+		}
+
+		var payload WebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("Invalid payload: %v", err)
+		}
+		received = append(received, payload)
+		w.WriteHeader(http.StatusNoContent)
+		close(done)
+	}))
+	defer receiver.Close()
+
+	channel, err := dbService.CreateNotificationChannel(&models.CreateNotificationChannelRequest{
+		Name:   "Webhook Test",
+		Type:   models.ChannelTypeWebhook,
+		URL:    receiver.URL,
+		Secret: "top-secret",
+		Events: []string{string(events.TypeWentOffline)},
+	})
+	if err != nil {
+		t.Fatal("Failed to create channel:", err)
+	}
+	defer dbService.DeleteNotificationChannel(channel.ID)
+	if channel.Secret == "top-secret" {
+		t.Error("Secret stored as plaintext")
+	}
+
+	dispatcher := NewDispatcher(dbService, &DispatcherConfig{
+		Workers:      1,
+		QueueSize:    10,
+		MaxAttempts:  3,
+		RetryBackoff: 10 * time.Millisecond,
+		Timeout:      time.Second,
+	})
+	bus := events.NewBus()
+	defer bus.Close()
+	dispatcher.Start(bus)
+	defer dispatcher.Stop()
+
+	server := models.Server{ID: 42, Name: "Test Server", Type: "minecraft", Address: "localhost", Port: 25565}
+	bus.Publish(events.Event{
+		Type:      events.TypeWentOnline, // Filtered out by the channel
+		Server:    server,
+		Timestamp: time.Now(),
+	})
+	bus.Publish(events.Event{
+		Type:      events.TypeWentOffline,
+		Server:    server,
+		Previous:  &models.ServerStatus{Online: true, Players: 5, MaxPlayers: 20},
+		Current:   &models.ServerStatus{Online: false},
+		Timestamp: time.Now(),
+		Message:   "Test Server went offline",
+	})
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Webhook was not delivered")
+	}
+
+	mutex.Lock()
+	payload := received[0]
+	mutex.Unlock()
+
+	if payload.Event != events.TypeWentOffline || payload.Server.ID != 42 {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+	if payload.OldStatus == nil || !payload.OldStatus.Online || payload.NewStatus == nil || payload.NewStatus.Online {
+		t.Errorf("Unexpected statuses in payload: %+v / %+v", payload.OldStatus, payload.NewStatus)
+	}
+
+	// The delivery is recorded right after the response is received
+	var deliveries []models.NotificationDelivery
+	for i := 0; i < 50; i++ {
+		deliveries, err = dbService.GetNotificationDeliveries(channel.ID, 10)
+		if err != nil {
+			t.Fatal("Failed to read deliveries:", err)
+		}
+		if len(deliveries) > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if len(deliveries) != 1 {
+		t.Fatalf("Expected 1 delivery, got %d", len(deliveries))
+	}
+	if deliveries[0].Status != models.DeliveryStatusSuccess || deliveries[0].Attempts != 2 ||
+		deliveries[0].ResponseCode != http.StatusNoContent {
+		t.Errorf("Unexpected delivery: %+v", deliveries[0])
+	}
+}
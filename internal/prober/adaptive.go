@@ -0,0 +1,102 @@
+package prober
+
+import (
+	"sync"
+	"time"
+)
+
+// Reasons for an adjusted probe interval, as reported in the prober stats
+const (
+	adaptiveNone    = ""
+	adaptiveConfirm = "confirm"         // Shortened to confirm a state change
+	adaptiveBackoff = "offline_backoff" // Lengthened for a long-offline server
+)
+
+// adaptiveState is what the adaptive scheduler knows about a server
+type adaptiveState struct {
+	online       bool
+	changed      bool      // The last probe changed the state
+	offlineSince time.Time // Zero while online
+	backoffSteps int       // Offline probes since the backoff started
+}
+
+// adaptiveScheduler adjusts probe intervals to a server's recent results:
+// a state change is confirmed after a short interval, and servers that
+// have been offline for long are probed less and less often, up to a cap.
+type adaptiveScheduler struct {
+	confirmInterval time.Duration
+	backoffAfter    time.Duration
+	maxInterval     time.Duration
+	states          map[uint]*adaptiveState
+	mutex           sync.Mutex
+}
+
+// newAdaptiveScheduler creates an adaptive scheduler; a zero confirm
+// interval or max interval disables the respective adjustment
+func newAdaptiveScheduler(confirmInterval, backoffAfter, maxInterval time.Duration) *adaptiveScheduler {
+	return &adaptiveScheduler{
+		confirmInterval: confirmInterval,
+		backoffAfter:    backoffAfter,
+		maxInterval:     maxInterval,
+		states:          make(map[uint]*adaptiveState),
+	}
+}
+
+// Observe records the result of a probe taken at the given time
+func (a *adaptiveScheduler) Observe(serverID uint, online bool, at time.Time) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	state, known := a.states[serverID]
+	if !known {
+		state = &adaptiveState{online: online}
+		a.states[serverID] = state
+	}
+	state.changed = known && state.online != online
+	state.online = online
+
+	switch {
+	case online:
+		state.offlineSince = time.Time{}
+		state.backoffSteps = 0
+	case state.offlineSince.IsZero():
+		state.offlineSince = at
+	case at.Sub(state.offlineSince) >= a.backoffAfter && state.backoffSteps < 32:
+		state.backoffSteps++
+	}
+}
+
+// Interval returns the interval until the next probe of a server with the
+// given base interval, and the reason if it was adjusted. The interval
+// doubles with every offline probe once the backoff started, but never
+// exceeds the cap; the cap never shortens the base interval.
+func (a *adaptiveScheduler) Interval(serverID uint, base time.Duration) (time.Duration, string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	state, known := a.states[serverID]
+	if !known {
+		return base, adaptiveNone
+	}
+
+	if state.changed && a.confirmInterval > 0 && a.confirmInterval < base {
+		return a.confirmInterval, adaptiveConfirm
+	}
+
+	if state.backoffSteps > 0 && a.maxInterval > base {
+		interval := base
+		for i := 0; i < state.backoffSteps && interval < a.maxInterval; i++ {
+			interval *= 2
+		}
+		return min(interval, a.maxInterval), adaptiveBackoff
+	}
+
+	return base, adaptiveNone
+}
+
+// Forget drops what is known about a server
+func (a *adaptiveScheduler) Forget(serverID uint) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	delete(a.states, serverID)
+}
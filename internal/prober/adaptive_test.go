@@ -0,0 +1,85 @@
+package prober
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveScheduler(t *testing.T) {
+	adaptive := newAdaptiveScheduler(5*time.Second, 10*time.Minute, 8*time.Minute)
+	base := time.Minute
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minute int) time.Time { return start.Add(time.Duration(minute) * time.Minute) }
+
+	expect := func(step string, interval time.Duration, reason string) {
+		t.Helper()
+		if got, gotReason := adaptive.Interval(1, base); got != interval || gotReason != reason {
+			t.Errorf("%s: expected %v (%q), got %v (%q)", step, interval, reason, got, gotReason)
+		}
+	}
+
+	// Unknown servers and the first result keep the base interval
+	expect("unknown", base, adaptiveNone)
+	adaptive.Observe(1, true, at(0))
+	expect("first probe", base, adaptiveNone)
+
+	// A state change is confirmed quickly, then the base interval applies again
+	adaptive.Observe(1, false, at(1))
+	expect("went offline", 5*time.Second, adaptiveConfirm)
+	adaptive.Observe(1, false, at(2))
+	expect("confirmed", base, adaptiveNone)
+
+	// Once offline for 10 minutes the interval doubles per probe up to the cap
+	adaptive.Observe(1, false, at(10))
+	expect("offline 9 minutes", base, adaptiveNone)
+	adaptive.Observe(1, false, at(11))
+	expect("offline 10 minutes", 2*time.Minute, adaptiveBackoff)
+	adaptive.Observe(1, false, at(13))
+	expect("second backoff", 4*time.Minute, adaptiveBackoff)
+	for minute := 17; minute < 60; minute += 8 {
+		adaptive.Observe(1, false, at(minute))
+	}
+	expect("capped", 8*time.Minute, adaptiveBackoff)
+
+	// Coming back online confirms quickly and resets the backoff
+	adaptive.Observe(1, true, at(60))
+	expect("back online", 5*time.Second, adaptiveConfirm)
+	adaptive.Observe(1, true, at(61))
+	expect("online", base, adaptiveNone)
+
+	// The cap never shortens a longer base interval, and confirming never
+	// lengthens a shorter one
+	if got, _ := adaptive.Interval(1, time.Hour); got != time.Hour {
+		t.Errorf("Expected the base interval, got %v", got)
+	}
+	adaptive.Observe(1, false, at(62))
+	if got, _ := adaptive.Interval(1, 2*time.Second); got != 2*time.Second {
+		t.Errorf("Expected the shorter base interval, got %v", got)
+	}
+
+	adaptive.Forget(1)
+	expect("forgotten", base, adaptiveNone)
+}
+
+func TestLoadBackgroundProberConfig(t *testing.T) {
+	t.Setenv("PROBE_ADAPTIVE", "true")
+	t.Setenv("PROBE_MAX_OFFLINE_INTERVAL", "30m")
+
+	config := LoadBackgroundProberConfig()
+	if !config.AdaptiveScheduling {
+		t.Error("Expected adaptive scheduling to be enabled")
+	}
+	if config.MaxOfflineInterval != 30*time.Minute {
+		t.Errorf("Expected a 30m cap, got %v", config.MaxOfflineInterval)
+	}
+
+	// Invalid values keep the defaults
+	t.Setenv("PROBE_ADAPTIVE", "sometimes")
+	t.Setenv("PROBE_MAX_OFFLINE_INTERVAL", "-5m")
+
+	config = LoadBackgroundProberConfig()
+	defaults := DefaultBackgroundProberConfig()
+	if config.AdaptiveScheduling != defaults.AdaptiveScheduling || config.MaxOfflineInterval != defaults.MaxOfflineInterval {
+		t.Errorf("Expected defaults, got %v and %v", config.AdaptiveScheduling, config.MaxOfflineInterval)
+	}
+}
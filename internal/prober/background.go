@@ -1,44 +1,124 @@
 package prober
 
 import (
+	"bytes"
 	"context"
 	"game-server-monitor/internal/cache"
 	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/maintenance"
 	"game-server-monitor/internal/models"
 	"log"
+	"os"
+	"strconv"
 	"sync"
 	"time"
 )
 
+// maxConcurrentProbes limits how many servers are probed at the same time
+const maxConcurrentProbes = 10
+
+// CycleHook is called once per probe interval with the configured servers
+// and their latest cached statuses
+type CycleHook func(servers []models.Server, statuses map[uint]*models.ServerStatus)
+
+// ProbeHook is called with every probe result stored for a server
+type ProbeHook func(server *models.Server, status *models.ServerStatus)
+
 // BackgroundProber manages background server probing tasks
 type BackgroundProber struct {
 	prober       ServerProber
 	cacheManager *cache.StatusCacheManager
 	dbService    *database.DatabaseService
+	eventBus     *events.Bus
+	flapDetector *flapDetector
+	adaptive     *adaptiveScheduler // nil unless adaptive scheduling is enabled
+	maintenance  *maintenance.Service
+	scheduler    *scheduler
+	semaphore    chan struct{} // Limits concurrent probes to maxConcurrentProbes
+	cycleHooks   []CycleHook
+	probeHooks   []ProbeHook
+	config       *BackgroundProberConfig
 	interval     time.Duration
 	ctx          context.Context
 	cancel       context.CancelFunc
 	wg           sync.WaitGroup
+	probes       sync.WaitGroup // Probes started by the loop
+	stats        probeStats
 	running      bool
+	paused       bool // No new probes are started while paused
 	mutex        sync.RWMutex
 }
 
 // BackgroundProberConfig holds configuration for the background prober
 type BackgroundProberConfig struct {
-	ProbeInterval time.Duration
-	CacheTTL      time.Duration
-	MaxRetries    int
+	ProbeInterval   time.Duration // Default time between probes of a server
+	ProbeTimeout    time.Duration // Default timeout of a probe attempt (0 uses the prober default)
+	ProbeJitter     float64       // Fraction of the interval by which probe times are randomly moved
+	CacheTTL        time.Duration // Minimum time a status is cached; longer for servers probed less often
+	MaxRetries      int           // Default attempts per probe
+	PlayerThreshold float64       // Fraction of max players that triggers threshold events (0 disables)
+	PingThreshold   int64         // Ping (ms) above which ping-degraded events are published (0 disables)
+
+	FlapWindow           time.Duration // Sliding window in which online/offline transitions are counted
+	FlapStartTransitions int           // Transitions in the window that mark a server as flapping (0 disables)
+	FlapEndTransitions   int           // Transitions in the window at or below which flapping ends
+
+	AdaptiveScheduling  bool          // Adjust intervals to recent probe results
+	ConfirmInterval     time.Duration // Interval after a state change, to confirm it quickly
+	OfflineBackoffAfter time.Duration // Time offline after which the interval starts growing
+	MaxOfflineInterval  time.Duration // Cap of the grown interval of offline servers
 }
 
 // DefaultBackgroundProberConfig returns default configuration
 func DefaultBackgroundProberConfig() *BackgroundProberConfig {
 	return &BackgroundProberConfig{
-		ProbeInterval: 30 * time.Second, // Probe every 30 seconds
-		CacheTTL:      5 * time.Minute,  // Cache for 5 minutes
-		MaxRetries:    3,                // Retry up to 3 times
+		ProbeInterval:   30 * time.Second, // Probe every 30 seconds
+		ProbeTimeout:    5 * time.Second,  // Give up an attempt after 5 seconds
+		ProbeJitter:     0.1,              // Spread probes by up to 10% of the interval
+		CacheTTL:        5 * time.Minute,  // Cache for 5 minutes
+		MaxRetries:      3,                // Retry up to 3 times
+		PlayerThreshold: 0.9,              // Notify when 90% of slots are taken
+		PingThreshold:   150,              // Notify when ping exceeds 150ms
+
+		FlapWindow:           10 * time.Minute, // Count transitions over the last 10 minutes
+		FlapStartTransitions: 4,                // 4 transitions (e.g. down, up, down, up) start flapping
+		FlapEndTransitions:   1,                // At most 1 transition ends flapping
+
+		AdaptiveScheduling:  false,
+		ConfirmInterval:     5 * time.Second,  // Confirm state changes after 5 seconds
+		OfflineBackoffAfter: 10 * time.Minute, // Back off once a server is offline for 10 minutes
+		MaxOfflineInterval:  10 * time.Minute, // Probe offline servers at least every 10 minutes
 	}
 }
 
+// LoadBackgroundProberConfig returns the default configuration with
+// adaptive scheduling set from the PROBE_ADAPTIVE and
+// PROBE_MAX_OFFLINE_INTERVAL environment variables
+func LoadBackgroundProberConfig() *BackgroundProberConfig {
+	config := DefaultBackgroundProberConfig()
+
+	if value := os.Getenv("PROBE_ADAPTIVE"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Printf("Warning: Ignoring PROBE_ADAPTIVE: %v", err)
+		} else {
+			config.AdaptiveScheduling = enabled
+		}
+	}
+
+	if value := os.Getenv("PROBE_MAX_OFFLINE_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			log.Printf("Warning: Ignoring PROBE_MAX_OFFLINE_INTERVAL: invalid duration %q", value)
+		} else {
+			config.MaxOfflineInterval = interval
+		}
+	}
+
+	return config
+}
+
 // NewBackgroundProber creates a new background prober
 func NewBackgroundProber(dbService *database.DatabaseService, config *BackgroundProberConfig) *BackgroundProber {
 	if config == nil {
@@ -47,10 +127,27 @@ func NewBackgroundProber(dbService *database.DatabaseService, config *Background
 
 	ctx, cancel := context.WithCancel(context.Background())
 
+	serverProber := NewServerProber()
+	if config.ProbeTimeout > 0 {
+		serverProber = NewServerProberWithTimeout(config.ProbeTimeout)
+	}
+
+	var adaptive *adaptiveScheduler
+	if config.AdaptiveScheduling {
+		adaptive = newAdaptiveScheduler(config.ConfirmInterval, config.OfflineBackoffAfter, config.MaxOfflineInterval)
+	}
+
 	return &BackgroundProber{
-		prober:       NewServerProber(),
+		prober:       serverProber,
 		cacheManager: cache.NewStatusCacheManagerWithTTL(config.CacheTTL),
 		dbService:    dbService,
+		eventBus:     events.NewBus(),
+		flapDetector: newFlapDetector(config.FlapWindow, config.FlapStartTransitions, config.FlapEndTransitions),
+		adaptive:     adaptive,
+		maintenance:  maintenance.NewService(dbService),
+		scheduler:    newScheduler(),
+		semaphore:    make(chan struct{}, maxConcurrentProbes),
+		config:       config,
 		interval:     config.ProbeInterval,
 		ctx:          ctx,
 		cancel:       cancel,
@@ -79,14 +176,17 @@ func (bp *BackgroundProber) Start() error {
 // Stop gracefully stops the background probing process
 func (bp *BackgroundProber) Stop() error {
 	bp.mutex.Lock()
-	defer bp.mutex.Unlock()
-
 	if !bp.running {
+		bp.mutex.Unlock()
 		return nil // Already stopped
 	}
 
 	bp.running = false
 	bp.cancel()
+	bp.mutex.Unlock()
+
+	// Cancelling aborts probes in flight; wait for them outside the lock,
+	// which they may need while finishing
 	bp.wg.Wait()
 
 	log.Println("Background prober stopped")
@@ -105,15 +205,71 @@ func (bp *BackgroundProber) GetCacheManager() *cache.StatusCacheManager {
 	return bp.cacheManager
 }
 
-// SetProbeInterval updates the probe interval (takes effect on next cycle)
+// GetEventBus returns the bus on which server state changes are published
+func (bp *BackgroundProber) GetEventBus() *events.Bus {
+	return bp.eventBus
+}
+
+// AddCycleHook registers a function to run after every probe cycle
+func (bp *BackgroundProber) AddCycleHook(hook CycleHook) {
+	bp.mutex.Lock()
+	defer bp.mutex.Unlock()
+	bp.cycleHooks = append(bp.cycleHooks, hook)
+}
+
+// AddProbeHook registers a function to run after every stored probe result
+func (bp *BackgroundProber) AddProbeHook(hook ProbeHook) {
+	bp.mutex.Lock()
+	defer bp.mutex.Unlock()
+	bp.probeHooks = append(bp.probeHooks, hook)
+}
+
+// SetProbeInterval updates the default probe interval. Servers without their
+// own interval use it from their next probe on.
 func (bp *BackgroundProber) SetProbeInterval(interval time.Duration) {
 	bp.mutex.Lock()
 	defer bp.mutex.Unlock()
 
 	bp.interval = interval
+	bp.scheduler.notify()
 	log.Printf("Probe interval updated to: %v", interval)
 }
 
+// Pause stops the loop from starting new probes until Resume is called.
+// Running probes finish and force probes still work.
+func (bp *BackgroundProber) Pause() {
+	bp.mutex.Lock()
+	defer bp.mutex.Unlock()
+
+	bp.paused = true
+	bp.scheduler.notify()
+	log.Println("Background prober paused")
+}
+
+// Resume lets the loop start probes again; servers that became due while
+// paused are probed right away
+func (bp *BackgroundProber) Resume() {
+	bp.mutex.Lock()
+	defer bp.mutex.Unlock()
+
+	bp.paused = false
+	bp.scheduler.notify()
+	log.Println("Background prober resumed")
+}
+
+// IsPaused returns whether the loop is paused
+func (bp *BackgroundProber) IsPaused() bool {
+	bp.mutex.RLock()
+	defer bp.mutex.RUnlock()
+	return bp.paused
+}
+
+// ProbeAll makes every scheduled server due right away and returns their
+// number. The probes run in the background once the loop is not paused.
+func (bp *BackgroundProber) ProbeAll() int {
+	return bp.scheduler.expediteAll()
+}
+
 // GetProbeInterval returns the current probe interval
 func (bp *BackgroundProber) GetProbeInterval() time.Duration {
 	bp.mutex.RLock()
@@ -121,104 +277,285 @@ func (bp *BackgroundProber) GetProbeInterval() time.Duration {
 	return bp.interval
 }
 
-// probeLoop is the main background probing loop
+// probeLoop is the main background probing loop. Servers are probed when
+// they are due according to their own interval; once per default interval
+// the schedule is synced with the database and the cycle hooks run.
 func (bp *BackgroundProber) probeLoop() {
 	defer bp.wg.Done()
+	defer bp.probes.Wait()
+
+	// Queue all servers on startup
+	bp.syncSchedule()
 
-	// Initial probe on startup
-	bp.probeAllServers()
+	syncInterval := bp.GetProbeInterval()
+	syncTicker := time.NewTicker(syncInterval)
+	defer syncTicker.Stop()
 
-	ticker := time.NewTicker(bp.interval)
-	defer ticker.Stop()
+	timer := time.NewTimer(syncInterval)
+	defer timer.Stop()
 
 	for {
+		if due, ok := bp.scheduler.next(); ok && !bp.IsPaused() {
+			timer.Reset(time.Until(due))
+		} else {
+			timer.Stop()
+		}
+
 		select {
 		case <-bp.ctx.Done():
 			log.Println("Background prober loop stopped")
 			return
-		case <-ticker.C:
-			// Update ticker interval if it changed
-			bp.mutex.RLock()
-			currentInterval := bp.interval
-			bp.mutex.RUnlock()
-
-			if ticker.C != nil && currentInterval != bp.interval {
-				ticker.Stop()
-				ticker = time.NewTicker(currentInterval)
+		case <-bp.scheduler.wake:
+			// Update the sync interval if it changed
+			if interval := bp.GetProbeInterval(); interval != syncInterval {
+				syncInterval = interval
+				syncTicker.Reset(interval)
+			}
+		case <-syncTicker.C:
+			bp.stats.endCycle()
+			if servers, ok := bp.syncSchedule(); ok {
+				bp.runCycleHooks(servers)
+			}
+		case <-timer.C:
+			if bp.IsPaused() {
+				continue
+			}
+			for _, probe := range bp.scheduler.popDue(time.Now()) {
+				bp.dispatch(probe)
 			}
-
-			bp.probeAllServers()
 		}
 	}
 }
 
-// probeAllServers probes all configured servers and updates cache
-func (bp *BackgroundProber) probeAllServers() {
+// syncSchedule queues new servers, applies configuration changes to queued
+// ones and drops deleted servers. It returns the configured servers.
+func (bp *BackgroundProber) syncSchedule() ([]models.Server, bool) {
 	servers, err := bp.dbService.GetAllServers()
 	if err != nil {
 		log.Printf("Failed to get servers from database: %v", err)
-		return
+		return nil, false
 	}
 
-	if len(servers) == 0 {
-		log.Println("No servers configured for probing")
-		return
+	for _, id := range bp.scheduler.sync(servers, bp.firstDue) {
+		bp.forgetServer(id)
+	}
+	return servers, true
+}
+
+// UpdateServer applies the configuration of a created or changed server to
+// the schedule. With reset its cached status and state history are dropped
+// and it is probed right away, in the background.
+func (bp *BackgroundProber) UpdateServer(server *models.Server, reset bool) {
+	if reset {
+		bp.forgetServer(server.ID)
+	}
+	bp.scheduler.update(*server, reset, bp.firstDue)
+}
+
+// RemoveServer stops probing a deleted server and drops its cached status
+func (bp *BackgroundProber) RemoveServer(serverID uint) {
+	bp.scheduler.remove(serverID)
+	bp.forgetServer(serverID)
+}
+
+// forgetServer drops the cached status and state history of a server
+func (bp *BackgroundProber) forgetServer(serverID uint) {
+	bp.cacheManager.RemoveServerStatus(serverID)
+	bp.flapDetector.Forget(serverID)
+	if bp.adaptive != nil {
+		bp.adaptive.Forget(serverID)
 	}
+}
+
+// dispatch probes a due server in the background and queues it again once
+// the probe finished
+func (bp *BackgroundProber) dispatch(probe dueProbe) {
+	bp.probes.Add(1)
+	go func() {
+		defer bp.probes.Done()
+
+		select {
+		case bp.semaphore <- struct{}{}:
+		case <-bp.ctx.Done():
+			return
+		}
+		bp.probeAndCacheServer(&probe.server)
+		<-bp.semaphore
+
+		if bp.ctx.Err() == nil {
+			bp.scheduler.reschedule(probe.entry, bp.nextDue)
+		}
+	}()
+}
+
+// intervalFor returns the probe interval of a server: its own if set, else the default
+func (bp *BackgroundProber) intervalFor(server *models.Server) time.Duration {
+	if server.ProbeIntervalSeconds > 0 {
+		return time.Duration(server.ProbeIntervalSeconds) * time.Second
+	}
+	return bp.GetProbeInterval()
+}
+
+// probeTargetChanged reports whether a configuration change makes earlier
+// probe results meaningless, e.g. because the server moved to another address
+func probeTargetChanged(previous, server *models.Server) bool {
+	return previous.Type != server.Type ||
+		previous.Address != server.Address ||
+		previous.Port != server.Port ||
+		previous.QueryPort != server.QueryPort ||
+		previous.EnableQuery != server.EnableQuery ||
+		previous.DualStack != server.DualStack ||
+		previous.Check != server.Check
+}
+
+// cacheTTLFor returns how long the status of a server is cached: the
+// configured TTL, or twice the server's effective interval if that is
+// longer, so the status does not expire before the next probe has finished
+func (bp *BackgroundProber) cacheTTLFor(server *models.Server) time.Duration {
+	interval, _ := bp.effectiveInterval(server)
+	return max(bp.config.CacheTTL, 2*bp.intervalFor(server), 2*interval)
+}
 
-	log.Printf("Starting probe cycle for %d servers", len(servers))
+// firstDue returns when a newly queued server is probed first: right away,
+// spread over a fraction of its interval
+func (bp *BackgroundProber) firstDue(server *models.Server) time.Time {
+	return time.Now().Add(spread(bp.intervalFor(server), bp.config.ProbeJitter))
+}
 
-	// Use goroutines to probe servers concurrently
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, 10) // Limit concurrent probes to 10
+// effectiveInterval returns the interval until the next probe of a server
+// after adaptive adjustments, and the reason if it was adjusted
+func (bp *BackgroundProber) effectiveInterval(server *models.Server) (time.Duration, string) {
+	interval := bp.intervalFor(server)
+	if bp.adaptive == nil {
+		return interval, adaptiveNone
+	}
+	return bp.adaptive.Interval(server.ID, interval)
+}
 
-	for _, server := range servers {
-		wg.Add(1)
-		go func(s models.Server) {
-			defer wg.Done()
+// nextDue returns when a probed server is due again: after its effective
+// interval, with jitter
+func (bp *BackgroundProber) nextDue(server *models.Server) time.Time {
+	interval, _ := bp.effectiveInterval(server)
+	return time.Now().Add(jitter(interval, bp.config.ProbeJitter))
+}
 
-			// Acquire semaphore
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
+// runCycleHooks passes the result of a probe cycle to the registered hooks
+func (bp *BackgroundProber) runCycleHooks(servers []models.Server) {
+	bp.mutex.RLock()
+	hooks := bp.cycleHooks
+	bp.mutex.RUnlock()
 
-			bp.probeAndCacheServer(&s)
-		}(server)
+	if len(hooks) == 0 {
+		return
 	}
 
-	wg.Wait()
-	log.Printf("Completed probe cycle for %d servers", len(servers))
+	statuses := bp.cacheManager.GetAllServerStatuses()
+	for _, hook := range hooks {
+		hook(servers, statuses)
+	}
 }
 
-// probeAndCacheServer probes a single server and updates the cache
+// probeAndCacheServer probes a single server and updates the cache. Probes
+// interrupted by Stop are discarded, as are results for servers that were
+// deleted or moved to another target while they were probed.
 func (bp *BackgroundProber) probeAndCacheServer(server *models.Server) {
 	startTime := time.Now()
 
 	// Probe the server with retry
-	status := bp.prober.ProbeServerWithRetry(server, 3)
+	status := bp.prober.ProbeServerWithRetry(bp.ctx, server, probeAttempts(server, bp.config.MaxRetries))
+	if bp.ctx.Err() != nil {
+		return
+	}
+	if current, ok := bp.scheduler.current(server.ID); !ok || probeTargetChanged(server, &current) {
+		log.Printf("Discarding outdated probe result for server %s", server.Name)
+		return
+	}
 
-	// Update cache with the result
-	bp.cacheManager.UpdateServerStatus(server.ID, status)
+	// Update cache and history with the result
+	bp.storeServerStatus(server, status)
 
 	duration := time.Since(startTime)
+	bp.stats.record(startTime, startTime.Add(duration), status.Online)
 
 	if status.Online {
-		log.Printf("Server %s (%s:%d) - Online: %d/%d players, ping: %dms, probe time: %v",
-			server.Name, server.Address, server.Port,
+		log.Printf("Server %s (%s) - Online: %d/%d players, ping: %dms, probe time: %v",
+			server.Name, describeTarget(server),
 			status.Players, status.MaxPlayers, status.Ping, duration)
 	} else {
-		log.Printf("Server %s (%s:%d) - Offline, probe time: %v",
-			server.Name, server.Address, server.Port, duration)
+		log.Printf("Server %s (%s) - Offline, probe time: %v",
+			server.Name, describeTarget(server), duration)
+	}
+}
+
+// storeServerStatus caches a probe result, persists it to the status history
+// and publishes the state changes since the previously cached status
+func (bp *BackgroundProber) storeServerStatus(server *models.Server, status *models.ServerStatus) {
+	previous, _ := bp.cacheManager.GetServerStatus(server.ID)
+
+	if period, err := bp.maintenance.Active(server.ID, status.LastUpdated); err != nil {
+		log.Printf("Failed to check maintenance windows for server %s: %v", server.Name, err)
+	} else {
+		status.Maintenance = period != nil
+	}
+
+	// Planned downtime does not count towards flapping
+	transition := previous != nil && previous.Online != status.Online && !status.Maintenance
+	flapping, flapChange := bp.flapDetector.Observe(server.ID, transition, status.LastUpdated)
+	status.Flapping = flapping
+
+	if bp.adaptive != nil {
+		bp.adaptive.Observe(server.ID, status.Online, status.LastUpdated)
+	}
+
+	bp.cacheManager.UpdateServerStatusWithTTL(server.ID, status, bp.cacheTTLFor(server))
+
+	if err := bp.dbService.RecordStatusSample(server.ID, status); err != nil {
+		log.Printf("Failed to record status history for server %s: %v", server.Name, err)
+	}
+
+	// Keep the last known icon; only write it when it changed
+	if len(status.Icon) > 0 && (previous == nil || !bytes.Equal(previous.Icon, status.Icon)) {
+		if _, err := bp.dbService.SaveServerIcon(server.ID, status.Icon); err != nil {
+			log.Printf("Failed to store icon for server %s: %v", server.Name, err)
+		}
+	}
+
+	detected := detectEvents(server, previous, status, bp.config)
+	if event := flapEvent(server, previous, status, flapChange, bp.config); event != nil {
+		detected = append(detected, *event)
+	}
+
+	for _, event := range detected {
+		log.Printf("Server %s: %s", server.Name, event.Message)
+		bp.eventBus.Publish(event)
+	}
+
+	bp.mutex.RLock()
+	hooks := bp.probeHooks
+	bp.mutex.RUnlock()
+	for _, hook := range hooks {
+		hook(server, status)
 	}
 }
 
-// ForceProbeServer immediately probes a specific server and updates cache
-func (bp *BackgroundProber) ForceProbeServer(serverID uint) (*models.ServerStatus, error) {
+// ForceProbeServer immediately probes a specific server and updates cache.
+// The probe is abandoned, without updating the cache, when ctx is done (e.g.
+// the HTTP client went away) or the prober is stopped.
+func (bp *BackgroundProber) ForceProbeServer(ctx context.Context, serverID uint) (*models.ServerStatus, error) {
 	server, err := bp.dbService.GetServer(serverID)
 	if err != nil {
 		return nil, err
 	}
 
-	status := bp.prober.ProbeServerWithRetry(server, 3)
-	bp.cacheManager.UpdateServerStatus(server.ID, status)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(bp.ctx, cancel)()
+
+	status := bp.prober.ProbeServerWithRetry(ctx, server, probeAttempts(server, bp.config.MaxRetries))
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	bp.storeServerStatus(server, status)
 
 	log.Printf("Force probed server %s: online=%t", server.Name, status.Online)
 	return status, nil
@@ -239,6 +576,53 @@ func (bp *BackgroundProber) GetProberStats() map[string]interface{} {
 	stats := bp.cacheManager.GetCacheStats()
 	stats["running"] = bp.IsRunning()
 	stats["probe_interval"] = bp.GetProbeInterval().String()
+	stats["paused"] = bp.IsPaused()
+	stats["queued_servers"] = bp.scheduler.Len()
+	stats["event_subscribers"] = bp.eventBus.SubscriberCount()
+	stats["flapping_servers"] = bp.flapDetector.Count()
+	stats["adaptive_scheduling"] = bp.adaptive != nil
+	bp.stats.addTo(stats)
+
+	schedule := bp.GetSchedule()
+	stats["schedule"] = schedule
+	probing := 0
+	for _, probe := range schedule {
+		if probe.Probing {
+			probing++
+		} else if _, ok := stats["next_probe"]; !ok {
+			stats["next_probe"] = probe.NextProbe
+		}
+	}
+	stats["probing_servers"] = probing
 
 	return stats
 }
+
+// ScheduledProbe is the next probe of a server
+type ScheduledProbe struct {
+	ServerID  uint      `json:"server_id"`
+	Name      string    `json:"name"`
+	NextProbe time.Time `json:"next_probe,omitzero"` // Unset while the server is being probed
+	Probing   bool      `json:"probing"`
+	Interval  string    `json:"interval"`           // Effective interval after adaptive adjustments
+	Adjusted  string    `json:"adjusted,omitempty"` // "confirm" or "offline_backoff" if adjusted
+}
+
+// GetSchedule returns the next probe of every scheduled server, servers
+// being probed first and the others in due order
+func (bp *BackgroundProber) GetSchedule() []ScheduledProbe {
+	scheduled := bp.scheduler.snapshot()
+	schedule := make([]ScheduledProbe, 0, len(scheduled))
+	for _, entry := range scheduled {
+		interval, adjusted := bp.effectiveInterval(&entry.server)
+		schedule = append(schedule, ScheduledProbe{
+			ServerID:  entry.server.ID,
+			Name:      entry.server.Name,
+			NextProbe: entry.due,
+			Probing:   entry.probing,
+			Interval:  interval.String(),
+			Adjusted:  adjusted,
+		})
+	}
+	return schedule
+}
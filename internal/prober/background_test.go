@@ -1,11 +1,50 @@
 package prober
 
 import (
+	"context"
+	"errors"
 	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"game-server-monitor/internal/protocol"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
 
+// countedProbes counts the probes of test_counted servers by server ID
+var countedProbes = struct {
+	counts map[uint]int
+	sync.Mutex
+}{counts: make(map[uint]int)}
+
+// blockingStarted receives the ID of every test_blocking server whose probe started
+var blockingStarted = make(chan uint, 10)
+
+func init() {
+	// A server type that never answers and only returns once the probe is cancelled
+	protocol.Register(protocol.ServerType{
+		Name: "test_blocking",
+		Prober: protocol.ProberFunc(func(ctx context.Context, server *models.Server) (*models.ServerStatus, error) {
+			blockingStarted <- server.ID
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}),
+	})
+
+	// A server type that is always online and counts its probes
+	protocol.Register(protocol.ServerType{
+		Name: "test_counted",
+		Prober: protocol.ProberFunc(func(ctx context.Context, server *models.Server) (*models.ServerStatus, error) {
+			countedProbes.Lock()
+			countedProbes.counts[server.ID]++
+			countedProbes.Unlock()
+			return &models.ServerStatus{Online: true, LastUpdated: time.Now()}, nil
+		}),
+	})
+}
+
 func TestBackgroundProber_StartStop(t *testing.T) {
 	// Skip this test for now as it requires a proper database setup
 	// This test would need a mock database service or test database
@@ -66,3 +105,355 @@ func TestProberService_Creation(t *testing.T) {
 		t.Errorf("Expected custom interval to be 5s, got %v", customService.GetProbeInterval())
 	}
 }
+
+func TestBackgroundProber_PerServerIntervals(t *testing.T) {
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+	dbService := database.NewDatabaseService()
+
+	// One server on its own 1s interval, one on the default interval
+	fast, err := dbService.ServerOps.CreateServer(&models.CreateServerRequest{Name: "Fast", Type: "test_counted", Address: "localhost", Port: 1, ProbeIntervalSeconds: 1})
+	if err != nil {
+		t.Fatal("Failed to create server:", err)
+	}
+	defer dbService.DeleteServer(fast.ID)
+	slow, err := dbService.ServerOps.CreateServer(&models.CreateServerRequest{Name: "Slow", Type: "test_counted", Address: "localhost", Port: 2})
+	if err != nil {
+		t.Fatal("Failed to create server:", err)
+	}
+	defer dbService.DeleteServer(slow.ID)
+
+	prober := NewBackgroundProber(dbService, &BackgroundProberConfig{
+		ProbeInterval: time.Minute,
+		CacheTTL:      time.Minute,
+		MaxRetries:    1,
+	})
+
+	// Probe hooks see every result, not just one per cycle
+	var hookCalls sync.Map
+	prober.AddProbeHook(func(server *models.Server, status *models.ServerStatus) {
+		count, _ := hookCalls.LoadOrStore(server.ID, new(atomic.Int32))
+		count.(*atomic.Int32).Add(1)
+	})
+
+	if err := prober.Start(); err != nil {
+		t.Fatal("Failed to start prober:", err)
+	}
+	time.Sleep(2500 * time.Millisecond)
+
+	// The stats report when the default interval server is due again
+	found := false
+	for _, probe := range prober.GetProberStats()["schedule"].([]ScheduledProbe) {
+		if probe.ServerID == slow.ID {
+			found = true
+			if probe.Interval != "1m0s" || time.Until(probe.NextProbe) < 50*time.Second {
+				t.Errorf("Expected the next probe in about a minute, got %+v", probe)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected the default interval server in the schedule")
+	}
+	prober.Stop()
+
+	countedProbes.Lock()
+	fastProbes, slowProbes := countedProbes.counts[fast.ID], countedProbes.counts[slow.ID]
+	countedProbes.Unlock()
+
+	if fastProbes < 3 {
+		t.Errorf("Expected the 1s server to be probed at least 3 times, got %d", fastProbes)
+	}
+	if count, ok := hookCalls.Load(fast.ID); !ok || int(count.(*atomic.Int32).Load()) != fastProbes {
+		t.Errorf("Expected the probe hook to run for each of the %d probes", fastProbes)
+	}
+	if slowProbes != 1 {
+		t.Errorf("Expected the default interval server to be probed once, got %d", slowProbes)
+	}
+	if status, ok := prober.GetServerStatus(fast.ID); !ok || !status.Online {
+		t.Errorf("Expected a cached online status, got %+v", status)
+	}
+}
+
+func TestBackgroundProber_StopCancelsProbes(t *testing.T) {
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+	dbService := database.NewDatabaseService()
+
+	server, err := dbService.ServerOps.CreateServer(&models.CreateServerRequest{Name: "Blocking", Type: "test_blocking", Address: "localhost", Port: 1, ProbeTimeoutSeconds: 60})
+	if err != nil {
+		t.Fatal("Failed to create server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	prober := NewBackgroundProber(dbService, &BackgroundProberConfig{
+		ProbeInterval: time.Minute,
+		CacheTTL:      time.Minute,
+		MaxRetries:    3,
+	})
+
+	// A force probe ends when its context does
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := prober.ForceProbeServer(ctx, server.ID); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	<-blockingStarted
+
+	if err := prober.Start(); err != nil {
+		t.Fatal("Failed to start prober:", err)
+	}
+	select {
+	case <-blockingStarted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the server to be probed")
+	}
+
+	// Stop does not wait for the probe's 60s timeout
+	start := time.Now()
+	prober.Stop()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected Stop to return promptly, took %v", elapsed)
+	}
+
+	// Interrupted probes do not count as offline results
+	if status, ok := prober.GetServerStatus(server.ID); ok {
+		t.Errorf("Expected no cached status, got %+v", status)
+	}
+}
+
+func TestBackgroundProber_ServerChanges(t *testing.T) {
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+	dbService := database.NewDatabaseService()
+
+	prober := NewBackgroundProber(dbService, &BackgroundProberConfig{
+		ProbeInterval: time.Minute,
+		CacheTTL:      time.Minute,
+		MaxRetries:    1,
+	})
+	if err := prober.Start(); err != nil {
+		t.Fatal("Failed to start prober:", err)
+	}
+	defer prober.Stop()
+
+	server, err := dbService.ServerOps.CreateServer(&models.CreateServerRequest{Name: "Changing", Type: "test_counted", Address: "localhost", Port: 1})
+	if err != nil {
+		t.Fatal("Failed to create server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	// waitForProbes waits until the server was probed the given number of times
+	waitForProbes := func(step string, want int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			countedProbes.Lock()
+			got := countedProbes.counts[server.ID]
+			countedProbes.Unlock()
+			if got == want {
+				return
+			}
+			if got > want || time.Now().After(deadline) {
+				t.Fatalf("%s: expected %d probes, got %d", step, want, got)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// New servers are probed right away, not after the next sync
+	prober.UpdateServer(server, true)
+	waitForProbes("created", 1)
+
+	// Changes that keep the probe target only update the schedule
+	renamed := *server
+	renamed.Name = "Renamed"
+	prober.UpdateServer(&renamed, false)
+	time.Sleep(200 * time.Millisecond)
+	waitForProbes("renamed", 1)
+
+	// Changed targets are probed again right away
+	moved := renamed
+	moved.Port = 2
+	if !probeTargetChanged(&renamed, &moved) {
+		t.Fatal("Expected a port change to change the probe target")
+	}
+	prober.UpdateServer(&moved, true)
+	waitForProbes("moved", 2)
+
+	// Deleted servers lose their status and are no longer scheduled
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := prober.GetServerStatus(server.ID); ok || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	prober.RemoveServer(server.ID)
+	if status, ok := prober.GetServerStatus(server.ID); ok {
+		t.Errorf("Expected no cached status after removal, got %+v", status)
+	}
+	for _, probe := range prober.GetSchedule() {
+		if probe.ServerID == server.ID {
+			t.Errorf("Expected the removed server not to be scheduled, got %+v", probe)
+		}
+	}
+}
+
+func TestBackgroundProber_PauseResume(t *testing.T) {
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+	dbService := database.NewDatabaseService()
+
+	server, err := dbService.ServerOps.CreateServer(&models.CreateServerRequest{Name: "Paused", Type: "test_counted", Address: "localhost", Port: 1})
+	if err != nil {
+		t.Fatal("Failed to create server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	probes := func() int {
+		countedProbes.Lock()
+		defer countedProbes.Unlock()
+		return countedProbes.counts[server.ID]
+	}
+
+	prober := NewBackgroundProber(dbService, &BackgroundProberConfig{
+		ProbeInterval: time.Minute,
+		CacheTTL:      time.Minute,
+		MaxRetries:    1,
+	})
+
+	// Nothing is probed while paused, even when asked to probe all servers
+	prober.Pause()
+	if err := prober.Start(); err != nil {
+		t.Fatal("Failed to start prober:", err)
+	}
+	defer prober.Stop()
+
+	// Wait for the server to be scheduled
+	deadline := time.Now().Add(2 * time.Second)
+	for prober.scheduler.Len() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := prober.ProbeAll(); n != 1 {
+		t.Errorf("Expected 1 queued server, got %d", n)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if n := probes(); n != 0 {
+		t.Fatalf("Expected no probes while paused, got %d", n)
+	}
+
+	// Due servers are probed once resumed; the stats count the probe once
+	// its result is stored
+	prober.Resume()
+	deadline = time.Now().Add(2 * time.Second)
+	stats := prober.GetProberStats()
+	for stats["total_probes"] == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		stats = prober.GetProberStats()
+	}
+	if n := probes(); n != 1 {
+		t.Fatalf("Expected 1 probe after resuming, got %d", n)
+	}
+	if stats["paused"] != false || stats["total_probes"] != 1 || stats["total_failures"] != 0 {
+		t.Errorf("Unexpected stats %v", stats)
+	}
+}
+
+func TestBackgroundProber_LongIntervalKeepsStatus(t *testing.T) {
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+	dbService := database.NewDatabaseService()
+
+	// The server's interval is far longer than the cache TTL
+	server, err := dbService.ServerOps.CreateServer(&models.CreateServerRequest{Name: "Slow", Type: "test_counted", Address: "localhost", Port: 1, ProbeIntervalSeconds: 3600})
+	if err != nil {
+		t.Fatal("Failed to create server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	prober := NewBackgroundProber(dbService, &BackgroundProberConfig{
+		ProbeInterval: time.Minute,
+		CacheTTL:      50 * time.Millisecond,
+		MaxRetries:    1,
+	})
+	if ttl := prober.cacheTTLFor(server); ttl != 2*time.Hour {
+		t.Errorf("Expected a TTL of twice the interval, got %v", ttl)
+	}
+
+	received := make(chan events.Event, 1)
+	unsubscribe := prober.GetEventBus().Subscribe("test", func(event events.Event) { received <- event }, events.TypeWentOffline)
+	defer unsubscribe()
+
+	prober.storeServerStatus(server, &models.ServerStatus{Online: true, LastUpdated: time.Now()})
+	time.Sleep(150 * time.Millisecond)
+
+	// The status outlives the configured TTL until the next probe
+	if status, ok := prober.GetServerStatus(server.ID); !ok || !status.Online {
+		t.Fatalf("Expected the cached status to survive, got %+v", status)
+	}
+
+	// so the next probe still sees the transition
+	prober.storeServerStatus(server, &models.ServerStatus{Online: false, LastUpdated: time.Now()})
+	select {
+	case event := <-received:
+		if event.Server.ID != server.ID {
+			t.Errorf("Expected an event for server %d, got %+v", server.ID, event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a went_offline event")
+	}
+}
+
+func TestBackgroundProber_OfflineBackoffKeepsStatus(t *testing.T) {
+	if err := database.Initialize(); err != nil {
+		t.Fatal("Failed to initialize test database:", err)
+	}
+	dbService := database.NewDatabaseService()
+
+	server, err := dbService.ServerOps.CreateServer(&models.CreateServerRequest{Name: "Backing off", Type: "test_counted", Address: "localhost", Port: 1})
+	if err != nil {
+		t.Fatal("Failed to create server:", err)
+	}
+	defer dbService.DeleteServer(server.ID)
+
+	// The backoff cap is far longer than the cache TTL and the base interval
+	prober := NewBackgroundProber(dbService, &BackgroundProberConfig{
+		ProbeInterval:       50 * time.Millisecond,
+		CacheTTL:            50 * time.Millisecond,
+		MaxRetries:          1,
+		AdaptiveScheduling:  true,
+		ConfirmInterval:     10 * time.Millisecond,
+		OfflineBackoffAfter: time.Millisecond,
+		MaxOfflineInterval:  time.Hour,
+	})
+
+	received := make(chan events.Event, 1)
+	unsubscribe := prober.GetEventBus().Subscribe("test", func(event events.Event) { received <- event }, events.TypeWentOnline)
+	defer unsubscribe()
+
+	// Offline long enough to back off
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		prober.storeServerStatus(server, &models.ServerStatus{Online: false, LastUpdated: start.Add(time.Duration(i) * time.Second)})
+	}
+	if interval, reason := prober.effectiveInterval(server); reason != adaptiveBackoff || interval <= 500*time.Millisecond {
+		t.Fatalf("Expected a backoff interval, got %v (%q)", interval, reason)
+	}
+	time.Sleep(150 * time.Millisecond)
+
+	// The offline status outlives the configured TTL, so coming back is an event
+	if _, ok := prober.GetServerStatus(server.ID); !ok {
+		t.Fatal("Expected the offline status to stay cached while backing off")
+	}
+	prober.storeServerStatus(server, &models.ServerStatus{Online: true, LastUpdated: start.Add(5 * time.Second)})
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("Expected a went_online event")
+	}
+}
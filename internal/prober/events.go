@@ -0,0 +1,117 @@
+package prober
+
+import (
+	"fmt"
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+)
+
+// detectEvents compares two consecutive statuses of a server and returns the
+// state-change events between them. The first status of a server (no
+// previous status) never produces events.
+func detectEvents(server *models.Server, previous, current *models.ServerStatus, config *BackgroundProberConfig) []events.Event {
+	if previous == nil || current == nil {
+		return nil
+	}
+
+	var detected []events.Event
+	newEvent := func(t events.Type, message string) events.Event {
+		return events.Event{
+			Type:      t,
+			Server:    *server,
+			Previous:  previous,
+			Current:   current,
+			Timestamp: current.LastUpdated,
+			Message:   message,
+		}
+	}
+
+	if previous.Online != current.Online {
+		if current.Online {
+			detected = append(detected, newEvent(events.TypeWentOnline,
+				fmt.Sprintf("%s is back online", server.Name)))
+		} else {
+			detected = append(detected, newEvent(events.TypeWentOffline,
+				fmt.Sprintf("%s went offline", server.Name)))
+		}
+		return detected
+	}
+
+	// The remaining checks only make sense while the server is reachable
+	if !current.Online {
+		return nil
+	}
+
+	if previous.Version != "" && current.Version != "" && previous.Version != current.Version {
+		detected = append(detected, newEvent(events.TypeVersionChanged,
+			fmt.Sprintf("%s changed version from %s to %s", server.Name, previous.Version, current.Version)))
+	}
+
+	if config.PlayerThreshold > 0 && current.MaxPlayers > 0 {
+		threshold := config.PlayerThreshold
+		before := playerRatio(previous)
+		after := playerRatio(current)
+
+		if before < threshold && after >= threshold {
+			event := newEvent(events.TypePlayerThresholdCrossed,
+				fmt.Sprintf("%s reached %d/%d players", server.Name, current.Players, current.MaxPlayers))
+			event.Threshold = threshold
+			event.Direction = events.DirectionUp
+			detected = append(detected, event)
+		} else if before >= threshold && after < threshold {
+			event := newEvent(events.TypePlayerThresholdCrossed,
+				fmt.Sprintf("%s dropped to %d/%d players", server.Name, current.Players, current.MaxPlayers))
+			event.Threshold = threshold
+			event.Direction = events.DirectionDown
+			detected = append(detected, event)
+		}
+	}
+
+	if config.PingThreshold > 0 && previous.Ping <= config.PingThreshold && current.Ping > config.PingThreshold {
+		event := newEvent(events.TypePingDegraded,
+			fmt.Sprintf("%s ping degraded to %dms", server.Name, current.Ping))
+		event.Threshold = float64(config.PingThreshold)
+		event.Direction = events.DirectionUp
+		detected = append(detected, event)
+	}
+
+	return detected
+}
+
+// flapEvent returns the flap_start or flap_end event for a change of the
+// flapping state, or nil when it did not change
+func flapEvent(server *models.Server, previous, current *models.ServerStatus, change int, config *BackgroundProberConfig) *events.Event {
+	event := &events.Event{
+		Server:    *server,
+		Previous:  previous,
+		Current:   current,
+		Timestamp: current.LastUpdated,
+	}
+
+	state := "offline"
+	if current.Online {
+		state = "online"
+	}
+
+	switch change {
+	case flapStarted:
+		event.Type = events.TypeFlapStart
+		event.Message = fmt.Sprintf("%s is flapping between online and offline (%d changes in %v)",
+			server.Name, config.FlapStartTransitions, config.FlapWindow)
+	case flapEnded:
+		event.Type = events.TypeFlapEnd
+		event.Message = fmt.Sprintf("%s stopped flapping and is %s", server.Name, state)
+	default:
+		return nil
+	}
+
+	return event
+}
+
+// playerRatio returns the fraction of player slots in use
+func playerRatio(status *models.ServerStatus) float64 {
+	if status.MaxPlayers <= 0 {
+		return 0
+	}
+	return float64(status.Players) / float64(status.MaxPlayers)
+}
@@ -0,0 +1,57 @@
+package prober
+
+import (
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"testing"
+)
+
+func TestDetectEvents(t *testing.T) {
+	config := DefaultBackgroundProberConfig()
+	server := &models.Server{ID: 1, Name: "Test Server"}
+
+	online := func(players int, version string, ping int64) *models.ServerStatus {
+		return &models.ServerStatus{Online: true, Players: players, MaxPlayers: 20, Version: version, Ping: ping}
+	}
+	offline := &models.ServerStatus{Online: false, Version: "Unknown"}
+
+	tests := []struct {
+		name      string
+		previous  *models.ServerStatus
+		current   *models.ServerStatus
+		expected  []events.Type
+		direction string
+	}{
+		{"first observation", nil, online(5, "1.20.1", 50), nil, ""},
+		{"went offline", online(5, "1.20.1", 50), offline, []events.Type{events.TypeWentOffline}, ""},
+		{"went online", offline, online(5, "1.20.1", 50), []events.Type{events.TypeWentOnline}, ""},
+		{"still offline", offline, offline, nil, ""},
+		{"unchanged", online(5, "1.20.1", 50), online(6, "1.20.1", 60), nil, ""},
+		{"version changed", online(5, "1.20.1", 50), online(5, "1.20.2", 50), []events.Type{events.TypeVersionChanged}, ""},
+		{"threshold crossed up", online(17, "1.20.1", 50), online(18, "1.20.1", 50), []events.Type{events.TypePlayerThresholdCrossed}, events.DirectionUp},
+		{"threshold crossed down", online(19, "1.20.1", 50), online(10, "1.20.1", 50), []events.Type{events.TypePlayerThresholdCrossed}, events.DirectionDown},
+		{"ping degraded", online(5, "1.20.1", 100), online(5, "1.20.1", 200), []events.Type{events.TypePingDegraded}, events.DirectionUp},
+		{"ping stays degraded", online(5, "1.20.1", 200), online(5, "1.20.1", 250), nil, ""},
+	}
+
+	for _, tt := range tests {
+		detected := detectEvents(server, tt.previous, tt.current, config)
+
+		if len(detected) != len(tt.expected) {
+			t.Errorf("%s: expected %d events, got %+v", tt.name, len(tt.expected), detected)
+			continue
+		}
+
+		for i, event := range detected {
+			if event.Type != tt.expected[i] {
+				t.Errorf("%s: expected %s event, got %s", tt.name, tt.expected[i], event.Type)
+			}
+			if event.Direction != tt.direction {
+				t.Errorf("%s: expected direction %q, got %q", tt.name, tt.direction, event.Direction)
+			}
+			if event.Server.ID != server.ID || event.Previous != tt.previous || event.Current != tt.current {
+				t.Errorf("%s: event does not reference the compared statuses", tt.name)
+			}
+		}
+	}
+}
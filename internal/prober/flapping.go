@@ -0,0 +1,93 @@
+package prober
+
+import (
+	"sync"
+	"time"
+)
+
+// Flap state changes returned by flapDetector.Observe
+const (
+	flapUnchanged = iota
+	flapStarted
+	flapEnded
+)
+
+// flapDetector tracks online/offline transitions per server over a sliding
+// window. A server starts flapping when the window holds at least
+// startTransitions transitions and stops once it holds endTransitions or fewer.
+type flapDetector struct {
+	window           time.Duration
+	startTransitions int
+	endTransitions   int
+	transitions      map[uint][]time.Time
+	flapping         map[uint]bool
+	mutex            sync.Mutex
+}
+
+// newFlapDetector creates a flap detector; startTransitions <= 0 disables detection
+func newFlapDetector(window time.Duration, startTransitions, endTransitions int) *flapDetector {
+	return &flapDetector{
+		window:           window,
+		startTransitions: startTransitions,
+		endTransitions:   endTransitions,
+		transitions:      make(map[uint][]time.Time),
+		flapping:         make(map[uint]bool),
+	}
+}
+
+// Observe records whether a server changed between online and offline at
+// time at, and returns whether it is flapping and how that state changed
+func (f *flapDetector) Observe(serverID uint, changed bool, at time.Time) (bool, int) {
+	if f.startTransitions <= 0 {
+		return false, flapUnchanged
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	// Drop transitions that left the window
+	history := f.transitions[serverID]
+	cutoff := at.Add(-f.window)
+	kept := history[:0]
+	for _, t := range history {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	if changed {
+		kept = append(kept, at)
+	}
+
+	if len(kept) == 0 {
+		delete(f.transitions, serverID)
+	} else {
+		f.transitions[serverID] = kept
+	}
+
+	wasFlapping := f.flapping[serverID]
+	switch {
+	case !wasFlapping && len(kept) >= f.startTransitions:
+		f.flapping[serverID] = true
+		return true, flapStarted
+	case wasFlapping && len(kept) <= f.endTransitions:
+		delete(f.flapping, serverID)
+		return false, flapEnded
+	default:
+		return wasFlapping, flapUnchanged
+	}
+}
+
+// Count returns the number of flapping servers
+func (f *flapDetector) Count() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return len(f.flapping)
+}
+
+// Forget drops the transition history of a server
+func (f *flapDetector) Forget(serverID uint) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.transitions, serverID)
+	delete(f.flapping, serverID)
+}
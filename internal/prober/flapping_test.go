@@ -0,0 +1,88 @@
+package prober
+
+import (
+	"game-server-monitor/internal/events"
+	"game-server-monitor/internal/models"
+	"testing"
+	"time"
+)
+
+func TestFlapDetector(t *testing.T) {
+	detector := newFlapDetector(10*time.Minute, 4, 1)
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minute int) time.Time { return start.Add(time.Duration(minute) * time.Minute) }
+
+	// Three transitions are not enough
+	for minute := 1; minute <= 3; minute++ {
+		if flapping, change := detector.Observe(1, true, at(minute)); flapping || change != flapUnchanged {
+			t.Fatalf("Minute %d: unexpected flapping=%v change=%d", minute, flapping, change)
+		}
+	}
+
+	// The fourth transition starts flapping, exactly once
+	if flapping, change := detector.Observe(1, true, at(4)); !flapping || change != flapStarted {
+		t.Fatalf("Expected flapping to start, got flapping=%v change=%d", flapping, change)
+	}
+	if flapping, change := detector.Observe(1, true, at(5)); !flapping || change != flapUnchanged {
+		t.Fatalf("Expected flapping to continue, got flapping=%v change=%d", flapping, change)
+	}
+	if detector.Count() != 1 {
+		t.Errorf("Expected 1 flapping server, got %d", detector.Count())
+	}
+
+	// Other servers are tracked separately
+	if flapping, _ := detector.Observe(2, true, at(5)); flapping {
+		t.Error("Expected server 2 not to be flapping")
+	}
+
+	// Stable probes keep flapping until the transitions leave the window
+	if flapping, change := detector.Observe(1, false, at(13)); !flapping || change != flapUnchanged {
+		t.Fatalf("Expected flapping while transitions are in the window, got flapping=%v change=%d", flapping, change)
+	}
+	if flapping, change := detector.Observe(1, false, at(15)); flapping || change != flapEnded {
+		t.Fatalf("Expected flapping to end, got flapping=%v change=%d", flapping, change)
+	}
+	if detector.Count() != 0 {
+		t.Errorf("Expected no flapping servers, got %d", detector.Count())
+	}
+
+	// Forgotten servers start over
+	for minute := 20; minute < 24; minute++ {
+		detector.Observe(1, true, at(minute))
+	}
+	detector.Forget(1)
+	if detector.Count() != 0 {
+		t.Errorf("Expected forgotten server not to be flapping, got %d", detector.Count())
+	}
+	if flapping, _ := detector.Observe(1, true, at(24)); flapping {
+		t.Error("Expected the transition history to be dropped")
+	}
+}
+
+func TestFlapDetector_Disabled(t *testing.T) {
+	detector := newFlapDetector(10*time.Minute, 0, 0)
+	for minute := 0; minute < 10; minute++ {
+		if flapping, _ := detector.Observe(1, true, time.Now()); flapping {
+			t.Fatal("Expected disabled detector never to report flapping")
+		}
+	}
+}
+
+func TestFlapEvent(t *testing.T) {
+	config := DefaultBackgroundProberConfig()
+	server := &models.Server{ID: 1, Name: "Test Server"}
+	current := &models.ServerStatus{Online: true, Flapping: true}
+
+	if event := flapEvent(server, nil, current, flapUnchanged, config); event != nil {
+		t.Errorf("Expected no event, got %+v", event)
+	}
+	if event := flapEvent(server, nil, current, flapStarted, config); event == nil || event.Type != events.TypeFlapStart {
+		t.Errorf("Expected flap_start event, got %+v", event)
+	}
+
+	current.Flapping = false
+	event := flapEvent(server, nil, current, flapEnded, config)
+	if event == nil || event.Type != events.TypeFlapEnd || event.Message != "Test Server stopped flapping and is online" {
+		t.Errorf("Expected flap_end event, got %+v", event)
+	}
+}
@@ -1,26 +1,36 @@
 package prober
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"game-server-monitor/internal/models"
+	"game-server-monitor/internal/protocol"
 	"log"
+	"net"
+	"strconv"
+	"sync"
 	"time"
-
-	"github.com/mcstatus-io/mcutil"
-	"github.com/rumblefrog/go-a2s"
 )
 
-// ServerProber interface defines the contract for server probing
+// ServerProber interface defines the contract for server probing. Probes
+// stop, including network I/O in flight and waits between retries, as soon
+// as ctx is done; the offline status returned then must not be taken as a
+// result, so callers check ctx.Err() first.
 type ServerProber interface {
-	ProbeMinecraft(address string, port int) (*models.ServerStatus, error)
-	ProbeCS2(address string, port int) (*models.ServerStatus, error)
-	ProbeServer(server *models.Server) *models.ServerStatus
-	ProbeServerWithRetry(server *models.Server, maxRetries int) *models.ServerStatus
+	ProbeServer(ctx context.Context, server *models.Server) *models.ServerStatus
+	ProbeServerWithRetry(ctx context.Context, server *models.Server, maxRetries int) *models.ServerStatus
 }
 
+// Retry policy used for servers that do not configure their own
+const (
+	defaultRetries      = 3
+	defaultRetryBackoff = time.Second
+	maxRetryBackoff     = 5 * time.Minute // Upper bound of the doubled backoff
+)
+
 // DefaultServerProber implements the ServerProber interface
 type DefaultServerProber struct {
-	timeout time.Duration
+	timeout time.Duration // Per-attempt timeout of servers without ProbeTimeoutSeconds
 }
 
 // NewServerProber creates a new server prober with default timeout
@@ -37,133 +47,182 @@ func NewServerProberWithTimeout(timeout time.Duration) ServerProber {
 	}
 }
 
-// ProbeMinecraft probes a Minecraft server using the mcutil library
-func (p *DefaultServerProber) ProbeMinecraft(address string, port int) (*models.ServerStatus, error) {
-	startTime := time.Now()
+// ProbeServer probes a server based on its type and handles errors, making
+// as many attempts as the server's ProbeRetries or defaultRetries
+func (p *DefaultServerProber) ProbeServer(ctx context.Context, server *models.Server) *models.ServerStatus {
+	return p.ProbeServerWithRetry(ctx, server, probeAttempts(server, defaultRetries))
+}
 
-	// Query the server status
-	response, err := mcutil.Status(address, uint16(port))
-	if err != nil {
-		return nil, fmt.Errorf("failed to query Minecraft server %s:%d: %w", address, port, err)
+// ProbeServerWithRetry probes a server with retry mechanism and error handling.
+// Each attempt uses the server's timeout, and failed attempts are retried with
+// the server's backoff. Dual-stack servers are probed over IPv4 and IPv6 separately.
+func (p *DefaultServerProber) ProbeServerWithRetry(ctx context.Context, server *models.Server, maxRetries int) *models.ServerStatus {
+	serverType, exists := protocol.Lookup(server.Type)
+	if !exists {
+		log.Printf("Unknown server type '%s' for server %s", server.Type, server.Name)
+		return offlineStatus()
 	}
 
-	// Calculate ping (use response latency if available, otherwise calculate from start time)
-	var ping int64
-	if response.Latency > 0 {
-		ping = response.Latency.Milliseconds()
-	} else {
-		ping = time.Since(startTime).Milliseconds()
+	if server.DualStack {
+		return p.probeDualStack(ctx, serverType, server, maxRetries)
 	}
 
-	// Parse player counts (handle nil pointers)
-	var players, maxPlayers int
-	if response.Players.Online != nil {
-		players = int(*response.Players.Online)
-	}
-	if response.Players.Max != nil {
-		maxPlayers = int(*response.Players.Max)
+	status, err := p.probeWithRetry(ctx, serverType, server, maxRetries)
+	if err != nil {
+		return failedStatus(err)
 	}
+	return status
+}
 
-	// Parse the response
-	serverStatus := &models.ServerStatus{
-		Online:      true,
-		Players:     players,
-		MaxPlayers:  maxPlayers,
-		Version:     response.Version.NameClean,
-		Ping:        ping,
-		LastUpdated: time.Now(),
-	}
+// probeWithRetry probes a server until it answers or maxRetries attempts
+// failed, returning the last error, or the error of ctx once it is done
+func (p *DefaultServerProber) probeWithRetry(ctx context.Context, serverType *protocol.ServerType, server *models.Server, maxRetries int) (*models.ServerStatus, error) {
+	var lastErr error
+	timeout := p.timeoutFor(server)
 
-	return serverStatus, nil
-}
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		status, err := serverType.Prober.Probe(attemptCtx, server)
+		cancel()
+
+		if err == nil {
+			return status, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 
-// ProbeCS2 probes a CS2/Source server using the Source Query protocol
-func (p *DefaultServerProber) ProbeCS2(address string, port int) (*models.ServerStatus, error) {
-	startTime := time.Now()
+		lastErr = err
 
-	// Create server address
-	serverAddr := fmt.Sprintf("%s:%d", address, port)
+		// Log the attempt failure
+		log.Printf("Probe attempt %d failed for server %s (%s): %v",
+			attempt+1, server.Name, describeTarget(server), err)
 
-	// Create A2S client
-	client, err := a2s.NewClient(serverAddr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create A2S client for %s: %w", serverAddr, err)
+		// Wait before retrying (exponential backoff)
+		if attempt < maxRetries-1 {
+			if err := sleepContext(ctx, retryBackoff(server, attempt)); err != nil {
+				return nil, err
+			}
+		}
 	}
-	defer client.Close()
 
-	// Query server info
-	info, err := client.QueryInfo()
-	if err != nil {
-		return nil, fmt.Errorf("failed to query CS2 server info %s: %w", serverAddr, err)
-	}
+	// All attempts failed, log final error
+	log.Printf("All probe attempts failed for server %s (%s): %v",
+		server.Name, describeTarget(server), lastErr)
 
-	// Calculate ping
-	ping := time.Since(startTime).Milliseconds()
+	return nil, lastErr
+}
 
-	// Parse the response
-	serverStatus := &models.ServerStatus{
-		Online:      true,
-		Players:     int(info.Players),
-		MaxPlayers:  int(info.MaxPlayers),
-		Version:     info.Version,
-		Ping:        ping,
-		LastUpdated: time.Now(),
+// probeDualStack probes a server over IPv4 and IPv6 concurrently. The server
+// is online if either family answers; the IPv4 result is reported unless
+// only IPv6 answered.
+func (p *DefaultServerProber) probeDualStack(ctx context.Context, serverType *protocol.ServerType, server *models.Server, maxRetries int) *models.ServerStatus {
+	families := []string{models.FamilyIPv4, models.FamilyIPv6}
+	statuses := make([]*models.ServerStatus, len(families))
+	results := make([]*models.FamilyStatus, len(families))
+	errs := make([]error, len(families))
+
+	var wg sync.WaitGroup
+	for i, family := range families {
+		wg.Add(1)
+		go func(i int, family string) {
+			defer wg.Done()
+
+			familyServer := *server
+			familyServer.Family = family
+
+			status, err := p.probeWithRetry(ctx, serverType, &familyServer, maxRetries)
+			if err != nil {
+				errs[i] = err
+				results[i] = &models.FamilyStatus{Online: false, Error: err.Error()}
+				return
+			}
+			statuses[i] = status
+			results[i] = &models.FamilyStatus{Online: true, Ping: status.Ping, Address: status.ResolvedAddress}
+		}(i, family)
 	}
+	wg.Wait()
 
-	return serverStatus, nil
-}
+	status := statuses[0]
+	if status == nil {
+		status = statuses[1]
+	}
+	if status == nil {
+		status = failedStatus(errors.Join(errs...))
+	}
 
-// ProbeServer probes a server based on its type and handles errors
-func (p *DefaultServerProber) ProbeServer(server *models.Server) *models.ServerStatus {
-	return p.ProbeServerWithRetry(server, 3)
+	status.IPv4 = results[0]
+	status.IPv6 = results[1]
+	status.PartiallyReachable = results[0].Online != results[1].Online
+	return status
 }
 
-// ProbeServerWithRetry probes a server with retry mechanism and error handling
-func (p *DefaultServerProber) ProbeServerWithRetry(server *models.Server, maxRetries int) *models.ServerStatus {
-	var lastErr error
+// timeoutFor returns the timeout of a single probe attempt of a server
+func (p *DefaultServerProber) timeoutFor(server *models.Server) time.Duration {
+	if server.ProbeTimeoutSeconds > 0 {
+		return time.Duration(server.ProbeTimeoutSeconds) * time.Second
+	}
+	return p.timeout
+}
 
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		var status *models.ServerStatus
-		var err error
-
-		switch server.Type {
-		case "minecraft":
-			status, err = p.ProbeMinecraft(server.Address, server.Port)
-		case "cs2":
-			status, err = p.ProbeCS2(server.Address, server.Port)
-		default:
-			log.Printf("Unknown server type '%s' for server %s", server.Type, server.Name)
-			return &models.ServerStatus{
-				Online:      false,
-				Players:     0,
-				MaxPlayers:  0,
-				Version:     "Unknown",
-				Ping:        0,
-				LastUpdated: time.Now(),
-			}
-		}
+// probeAttempts returns how often a server is probed before it counts as
+// offline: its ProbeRetries if set, else fallback, and at least once
+func probeAttempts(server *models.Server, fallback int) int {
+	if server.ProbeRetries > 0 {
+		return server.ProbeRetries
+	}
+	return max(fallback, 1)
+}
 
-		if err == nil {
-			return status
-		}
+// retryBackoff returns how long to wait after the given failed attempt
+// (counting from 0): the server's backoff, doubled for every further
+// attempt up to maxRetryBackoff
+func retryBackoff(server *models.Server, attempt int) time.Duration {
+	backoff := defaultRetryBackoff
+	if server.RetryBackoffSeconds > 0 {
+		backoff = time.Duration(server.RetryBackoffSeconds) * time.Second
+	}
+	return min(backoff<<attempt, maxRetryBackoff)
+}
 
-		lastErr = err
+// sleepContext waits for d, or returns the error of ctx if it is done first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
 
-		// Log the attempt failure
-		log.Printf("Probe attempt %d failed for server %s (%s:%d): %v",
-			attempt+1, server.Name, server.Address, server.Port, err)
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
 
-		// Wait before retrying (exponential backoff)
-		if attempt < maxRetries-1 {
-			waitTime := time.Duration(attempt+1) * time.Second
-			time.Sleep(waitTime)
-		}
+// describeTarget returns the address of a server for log messages
+func describeTarget(server *models.Server) string {
+	address := server.Address
+	if server.Port != 0 {
+		address = net.JoinHostPort(server.Address, strconv.Itoa(server.Port))
+	}
+	if server.Family != "" {
+		address += " over " + server.Family
 	}
+	return address
+}
 
-	// All attempts failed, log final error and return offline status
-	log.Printf("All probe attempts failed for server %s (%s:%d): %v",
-		server.Name, server.Address, server.Port, lastErr)
+// failedStatus returns the status reported for a failed probe, with the
+// certificate details of HTTPS checks whose certificate was rejected
+func failedStatus(err error) *models.ServerStatus {
+	status := offlineStatus()
+	var certErr *protocol.CertificateError
+	if errors.As(err, &certErr) {
+		status.TLS = certErr.TLS
+	}
+	return status
+}
 
+// offlineStatus returns the status reported for servers that did not answer
+func offlineStatus() *models.ServerStatus {
 	return &models.ServerStatus{
 		Online:      false,
 		Players:     0,
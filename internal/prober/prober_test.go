@@ -1,7 +1,13 @@
 package prober
 
 import (
+	"context"
+	"errors"
 	"game-server-monitor/internal/models"
+	"game-server-monitor/internal/protocol"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -47,7 +53,7 @@ func TestProbeServerWithUnknownType(t *testing.T) {
 		Port:    25565,
 	}
 
-	status := prober.ProbeServer(server)
+	status := prober.ProbeServer(context.Background(), server)
 
 	if status.Online {
 		t.Error("Expected server to be offline for unknown type")
@@ -70,7 +76,7 @@ func TestProbeServerWithRetryOfflineServer(t *testing.T) {
 		Port:    25565,
 	}
 
-	status := prober.ProbeServerWithRetry(server, 1) // Only 1 retry for faster test
+	status := prober.ProbeServerWithRetry(context.Background(), server, 1) // Only 1 retry for faster test
 
 	if status.Online {
 		t.Error("Expected server to be offline")
@@ -84,3 +90,175 @@ func TestProbeServerWithRetryOfflineServer(t *testing.T) {
 		t.Errorf("Expected 0 max players, got %d", status.MaxPlayers)
 	}
 }
+
+// stubResolver resolves every host to fixed addresses and has no SRV records
+type stubResolver struct {
+	ips []net.IP
+}
+
+func (r stubResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	return "", nil, errors.New("no SRV records")
+}
+
+func (r stubResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	var ips []net.IP
+	for _, ip := range r.ips {
+		if network == "ip" || (network == "ip4") == (ip.To4() != nil) {
+			ips = append(ips, ip)
+		}
+	}
+	return ips, nil
+}
+
+func init() {
+	// A server type that only answers over IPv4
+	protocol.Register(protocol.ServerType{
+		Name: "test_ipv4_only",
+		Prober: protocol.ProberFunc(func(ctx context.Context, server *models.Server) (*models.ServerStatus, error) {
+			address, err := protocol.ResolveAddress(ctx, server, server.Port)
+			if err != nil {
+				return nil, err
+			}
+			if server.Family == models.FamilyIPv6 {
+				return nil, errors.New("connection refused")
+			}
+			return &models.ServerStatus{Online: true, Ping: 12, ResolvedAddress: address, LastUpdated: time.Now()}, nil
+		}),
+	})
+}
+
+func TestProbeServerDualStack(t *testing.T) {
+	previous := protocol.SetResolver(stubResolver{ips: []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.1")}})
+	defer protocol.SetResolver(previous)
+
+	prober := NewServerProber()
+	server := &models.Server{Name: "Dual", Type: "test_ipv4_only", Address: "dual.example.com", Port: 1234, DualStack: true}
+
+	status := prober.ProbeServerWithRetry(context.Background(), server, 1)
+
+	if !status.Online || !status.PartiallyReachable {
+		t.Errorf("Expected an online, partially reachable server, got %+v", status)
+	}
+	if status.IPv4 == nil || !status.IPv4.Online || status.IPv4.Ping != 12 || status.IPv4.Address != "192.0.2.1:1234" {
+		t.Errorf("Unexpected IPv4 result: %+v", status.IPv4)
+	}
+	if status.IPv6 == nil || status.IPv6.Online || status.IPv6.Error == "" {
+		t.Errorf("Unexpected IPv6 result: %+v", status.IPv6)
+	}
+
+	// Without dual-stack the server is probed once over any family
+	server.DualStack = false
+	status = prober.ProbeServerWithRetry(context.Background(), server, 1)
+	if status.IPv4 != nil || status.IPv6 != nil || status.PartiallyReachable {
+		t.Errorf("Expected no per-family results, got %+v", status)
+	}
+}
+
+// failingAttempts records the time left until the deadline of each probe of the test_failing type
+var failingAttempts []time.Duration
+
+func init() {
+	// A server type that never answers
+	protocol.Register(protocol.ServerType{
+		Name: "test_failing",
+		Prober: protocol.ProberFunc(func(ctx context.Context, server *models.Server) (*models.ServerStatus, error) {
+			deadline, _ := ctx.Deadline()
+			failingAttempts = append(failingAttempts, time.Until(deadline))
+			return nil, errors.New("connection refused")
+		}),
+	})
+}
+
+func TestProbeServerRetryPolicy(t *testing.T) {
+	failingAttempts = nil
+	prober := NewServerProber()
+	server := &models.Server{Name: "Failing", Type: "test_failing", Address: "localhost", Port: 1234, ProbeRetries: 2, ProbeTimeoutSeconds: 7}
+
+	start := time.Now()
+	status := prober.ProbeServer(context.Background(), server)
+
+	if status.Online {
+		t.Error("Expected server to be offline")
+	}
+	if len(failingAttempts) != 2 || failingAttempts[0] < 6*time.Second || failingAttempts[0] > 7*time.Second {
+		t.Errorf("Expected 2 attempts with a 7s timeout, got %v", failingAttempts)
+	}
+	if elapsed := time.Since(start); elapsed < defaultRetryBackoff {
+		t.Errorf("Expected a backoff between attempts, took %v", elapsed)
+	}
+}
+
+func TestRetryPolicyDefaults(t *testing.T) {
+	server := &models.Server{}
+
+	if attempts := probeAttempts(server, 5); attempts != 5 {
+		t.Errorf("Expected the fallback of 5 attempts, got %d", attempts)
+	}
+	if attempts := probeAttempts(server, 0); attempts != 1 {
+		t.Errorf("Expected at least 1 attempt, got %d", attempts)
+	}
+	server.ProbeRetries = 2
+	if attempts := probeAttempts(server, 5); attempts != 2 {
+		t.Errorf("Expected the server's 2 attempts, got %d", attempts)
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	for attempt, want := range expected {
+		if got := retryBackoff(server, attempt); got != want {
+			t.Errorf("Attempt %d: expected backoff %v, got %v", attempt, want, got)
+		}
+	}
+	server.RetryBackoffSeconds = 10
+	if got := retryBackoff(server, 1); got != 20*time.Second {
+		t.Errorf("Expected a doubled server backoff of 20s, got %v", got)
+	}
+	if got := retryBackoff(server, 9); got != maxRetryBackoff {
+		t.Errorf("Expected the backoff to be capped at %v, got %v", maxRetryBackoff, got)
+	}
+
+	prober := &DefaultServerProber{timeout: 5 * time.Second}
+	if timeout := prober.timeoutFor(&models.Server{}); timeout != 5*time.Second {
+		t.Errorf("Expected the default timeout, got %v", timeout)
+	}
+}
+
+func TestProbeServerWithRetry_Cancel(t *testing.T) {
+	prober := NewServerProber()
+	server := &models.Server{Name: "Failing", Type: "test_failing", Address: "localhost", Port: 1234, ProbeRetries: 3, RetryBackoffSeconds: 60}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	// Cancelling ends the backoff wait after the first attempt
+	start := time.Now()
+	status := prober.ProbeServerWithRetry(ctx, server, server.ProbeRetries)
+
+	if status.Online {
+		t.Error("Expected server to be offline")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected probing to stop promptly, took %v", elapsed)
+	}
+}
+
+func TestProbeServerCertificateError(t *testing.T) {
+	// Not trusted by the system roots
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	address := server.Listener.Addr().(*net.TCPAddr)
+
+	prober := NewServerProberWithTimeout(time.Second)
+	status := prober.ProbeServerWithRetry(context.Background(), &models.Server{
+		Name:    "HTTPS Check",
+		Type:    "https",
+		Address: address.IP.String(),
+		Port:    address.Port,
+	}, 1)
+
+	if status.Online {
+		t.Fatal("Expected the server to be offline")
+	}
+	if status.TLS == nil || !status.TLS.NotAfter.Equal(server.Certificate().NotAfter) || status.TLS.Error == "" {
+		t.Errorf("Expected the rejected certificate, got %+v", status.TLS)
+	}
+}
@@ -0,0 +1,299 @@
+package prober
+
+import (
+	"container/heap"
+	"game-server-monitor/internal/models"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+// scheduleEntry is a server known to the scheduler and the time its next probe is due
+type scheduleEntry struct {
+	server  models.Server
+	due     time.Time
+	index   int  // Position in the queue, -1 while the server is being probed
+	reprobe bool // Probe again right away once the running probe finished
+}
+
+// dueProbe is a server taken from the queue for probing, with a copy of its configuration
+type dueProbe struct {
+	entry  *scheduleEntry
+	server models.Server
+}
+
+// probeQueue is a min-heap of schedule entries ordered by due time
+type probeQueue []*scheduleEntry
+
+func (q probeQueue) Len() int { return len(q) }
+
+func (q probeQueue) Less(i, j int) bool { return q[i].due.Before(q[j].due) }
+
+func (q probeQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *probeQueue) Push(x any) {
+	entry := x.(*scheduleEntry)
+	entry.index = len(*q)
+	*q = append(*q, entry)
+}
+
+func (q *probeQueue) Pop() any {
+	old := *q
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	entry.index = -1
+	*q = old[:len(old)-1]
+	return entry
+}
+
+// scheduler keeps servers in a priority queue of their next probe times.
+// Servers taken from the queue for probing stay known to the scheduler
+// until they are rescheduled or removed.
+type scheduler struct {
+	queue   probeQueue
+	entries map[uint]*scheduleEntry
+	wake    chan struct{} // Signalled when the earliest due time may have changed
+	mutex   sync.Mutex
+}
+
+// newScheduler creates an empty scheduler
+func newScheduler() *scheduler {
+	return &scheduler{
+		entries: make(map[uint]*scheduleEntry),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// sync makes the scheduler track exactly the given servers. Configuration
+// changes are applied to known servers, new servers are queued at the time
+// returned by firstDue and servers that are gone are dropped. It returns the
+// IDs of the dropped servers.
+func (s *scheduler) sync(servers []models.Server, firstDue func(server *models.Server) time.Time) []uint {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current := make(map[uint]bool, len(servers))
+	for _, server := range servers {
+		current[server.ID] = true
+
+		if entry, exists := s.entries[server.ID]; exists {
+			entry.server = server
+			continue
+		}
+
+		entry := &scheduleEntry{server: server, due: firstDue(&server)}
+		s.entries[server.ID] = entry
+		heap.Push(&s.queue, entry)
+	}
+
+	var removed []uint
+	for id := range s.entries {
+		if !current[id] {
+			s.removeLocked(id)
+			removed = append(removed, id)
+		}
+	}
+
+	s.notify()
+	return removed
+}
+
+// update applies a server's configuration, adding the server at the time
+// returned by firstDue if it is unknown. With probeNow the server is due
+// right away, or once the running probe finished if it is being probed.
+func (s *scheduler) update(server models.Server, probeNow bool, firstDue func(server *models.Server) time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, exists := s.entries[server.ID]
+	switch {
+	case !exists:
+		entry = &scheduleEntry{server: server, due: firstDue(&server)}
+		if probeNow {
+			entry.due = time.Now()
+		}
+		s.entries[server.ID] = entry
+		heap.Push(&s.queue, entry)
+	case !probeNow:
+		entry.server = server
+	case entry.index < 0:
+		entry.server = server
+		entry.reprobe = true
+	default:
+		entry.server = server
+		entry.due = time.Now()
+		heap.Fix(&s.queue, entry.index)
+	}
+
+	s.notify()
+}
+
+// expediteAll makes all servers due right away, those being probed once the
+// running probe finished, and returns the number of servers
+func (s *scheduler) expediteAll() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for _, entry := range s.entries {
+		if entry.index < 0 {
+			entry.reprobe = true
+		} else {
+			entry.due = now
+		}
+	}
+	heap.Init(&s.queue)
+
+	s.notify()
+	return len(s.entries)
+}
+
+// current returns the configuration a server is scheduled with, or false
+// if the server is no longer scheduled
+func (s *scheduler) current(serverID uint) (models.Server, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, exists := s.entries[serverID]
+	if !exists {
+		return models.Server{}, false
+	}
+	return entry.server, true
+}
+
+// remove stops scheduling a server
+func (s *scheduler) remove(serverID uint) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.removeLocked(serverID)
+}
+
+// removeLocked stops scheduling a server; the caller holds the mutex
+func (s *scheduler) removeLocked(serverID uint) {
+	entry, exists := s.entries[serverID]
+	if !exists {
+		return
+	}
+	if entry.index >= 0 {
+		heap.Remove(&s.queue, entry.index)
+	}
+	delete(s.entries, serverID)
+}
+
+// next returns the earliest due time, or false if no server is queued
+func (s *scheduler) next() (time.Time, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.queue) == 0 {
+		return time.Time{}, false
+	}
+	return s.queue[0].due, true
+}
+
+// popDue takes all servers due at or before now from the queue
+func (s *scheduler) popDue(now time.Time) []dueProbe {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var due []dueProbe
+	for len(s.queue) > 0 && !s.queue[0].due.After(now) {
+		entry := heap.Pop(&s.queue).(*scheduleEntry)
+		due = append(due, dueProbe{entry: entry, server: entry.server})
+	}
+	return due
+}
+
+// reschedule queues a probed server again, unless it was removed meanwhile.
+// next computes the due time from the server's current configuration,
+// unless an update asked for another probe right away.
+func (s *scheduler) reschedule(entry *scheduleEntry, next func(server *models.Server) time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.entries[entry.server.ID] != entry || entry.index >= 0 {
+		return
+	}
+	if entry.reprobe {
+		entry.due = time.Now()
+		entry.reprobe = false
+	} else {
+		entry.due = next(&entry.server)
+	}
+	heap.Push(&s.queue, entry)
+	s.notify()
+}
+
+// scheduledServer is a server known to the scheduler and its next due time
+type scheduledServer struct {
+	server  models.Server
+	due     time.Time // Zero while the server is being probed
+	probing bool
+}
+
+// snapshot returns all known servers, those being probed first and the
+// queued ones in due order
+func (s *scheduler) snapshot() []scheduledServer {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	servers := make([]scheduledServer, 0, len(s.entries))
+	for _, entry := range s.entries {
+		if entry.index < 0 {
+			servers = append(servers, scheduledServer{server: entry.server, probing: true})
+		} else {
+			servers = append(servers, scheduledServer{server: entry.server, due: entry.due})
+		}
+	}
+
+	sort.Slice(servers, func(i, j int) bool {
+		if servers[i].probing != servers[j].probing {
+			return servers[i].probing
+		}
+		if !servers[i].due.Equal(servers[j].due) {
+			return servers[i].due.Before(servers[j].due)
+		}
+		return servers[i].server.ID < servers[j].server.ID
+	})
+	return servers
+}
+
+// Len returns the number of queued servers, not counting those being probed
+func (s *scheduler) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.queue)
+}
+
+// notify wakes the scheduling loop without blocking
+func (s *scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// jitter randomly moves a duration by up to fraction of its length in
+// either direction, so servers with the same interval spread out over time
+func jitter(d time.Duration, fraction float64) time.Duration {
+	spread := time.Duration(float64(d) * fraction)
+	if spread <= 0 {
+		return d
+	}
+	return d - spread + rand.N(2*spread+1)
+}
+
+// spread returns a random delay of up to fraction of d, used to spread the
+// first probes of servers that are queued together
+func spread(d time.Duration, fraction float64) time.Duration {
+	limit := time.Duration(float64(d) * fraction)
+	if limit <= 0 {
+		return 0
+	}
+	return rand.N(limit + 1)
+}
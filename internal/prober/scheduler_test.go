@@ -0,0 +1,137 @@
+package prober
+
+import (
+	"game-server-monitor/internal/models"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	s := newScheduler()
+	now := time.Now()
+
+	// Queue servers due 3s, 1s and 2s from now
+	offsets := map[uint]time.Duration{1: 3 * time.Second, 2: time.Second, 3: 2 * time.Second}
+	firstDue := func(server *models.Server) time.Time { return now.Add(offsets[server.ID]) }
+	s.sync([]models.Server{{ID: 1}, {ID: 2}, {ID: 3}}, firstDue)
+
+	if next, ok := s.next(); !ok || !next.Equal(now.Add(time.Second)) {
+		t.Errorf("Expected the earliest due time first, got %v", next)
+	}
+	if due := s.popDue(now); len(due) != 0 {
+		t.Errorf("Expected nothing due yet, got %d servers", len(due))
+	}
+
+	due := s.popDue(now.Add(2 * time.Second))
+	if len(due) != 2 || due[0].server.ID != 2 || due[1].server.ID != 3 {
+		t.Fatalf("Expected servers 2 and 3 in due order, got %+v", due)
+	}
+	if s.Len() != 1 {
+		t.Errorf("Expected 1 queued server, got %d", s.Len())
+	}
+
+	// Configuration changes reach servers being probed; removed servers are
+	// not queued again
+	if snapshot := s.snapshot(); len(snapshot) != 3 || !snapshot[0].probing || !snapshot[1].probing ||
+		snapshot[2].server.ID != 1 || !snapshot[2].due.Equal(now.Add(3*time.Second)) {
+		t.Errorf("Expected probing servers first, then server 1, got %+v", snapshot)
+	}
+
+	if removed := s.sync([]models.Server{{ID: 1}, {ID: 2, Name: "renamed"}}, firstDue); len(removed) != 1 || removed[0] != 3 {
+		t.Errorf("Expected server 3 to be removed, got %v", removed)
+	}
+	later := func(server *models.Server) time.Time { return now.Add(10 * time.Second) }
+	s.reschedule(due[0].entry, later)
+	s.reschedule(due[1].entry, later)
+
+	if s.Len() != 2 {
+		t.Fatalf("Expected 2 queued servers, got %d", s.Len())
+	}
+	due = s.popDue(now.Add(time.Minute))
+	if len(due) != 2 || due[0].server.ID != 1 || due[1].server.ID != 2 || due[1].server.Name != "renamed" {
+		t.Errorf("Unexpected due servers %+v", due)
+	}
+
+	// Servers removed from the queue are dropped
+	s.reschedule(due[0].entry, later)
+	s.remove(1)
+	if s.Len() != 0 {
+		t.Errorf("Expected an empty queue, got %d", s.Len())
+	}
+	if _, ok := s.next(); ok {
+		t.Error("Expected no due time for an empty queue")
+	}
+}
+
+func TestScheduler_Update(t *testing.T) {
+	s := newScheduler()
+	now := time.Now()
+	later := func(server *models.Server) time.Time { return now.Add(time.Minute) }
+
+	// Unknown servers are added, right away or at their first due time
+	s.update(models.Server{ID: 1}, true, later)
+	s.update(models.Server{ID: 2}, false, later)
+	due := s.popDue(time.Now())
+	if len(due) != 1 || due[0].server.ID != 1 {
+		t.Fatalf("Expected server 1 to be due right away, got %+v", due)
+	}
+
+	// Queued servers are moved forward only when asked to
+	s.update(models.Server{ID: 2, Name: "renamed"}, false, later)
+	if next, _ := s.next(); !next.Equal(now.Add(time.Minute)) {
+		t.Errorf("Expected server 2 to keep its due time, got %v", next)
+	}
+	s.update(models.Server{ID: 2, Port: 2}, true, later)
+	if current, ok := s.current(2); !ok || current.Port != 2 || current.Name != "" {
+		t.Errorf("Expected the updated configuration, got %+v", current)
+	}
+	if due := s.popDue(time.Now()); len(due) != 1 || due[0].server.ID != 2 {
+		t.Errorf("Expected server 2 to be due right away, got %+v", due)
+	}
+
+	// Servers being probed are probed again once the probe finished
+	s.update(models.Server{ID: 1, Port: 2}, true, later)
+	s.reschedule(due[0].entry, later)
+	if next, _ := s.next(); next.After(time.Now()) {
+		t.Errorf("Expected server 1 to be due right away, got %v", next)
+	}
+
+	if _, ok := s.current(3); ok {
+		t.Error("Expected unknown servers not to be current")
+	}
+
+	// All servers can be made due at once; servers being probed follow once
+	// their probe finished
+	s.update(models.Server{ID: 3}, false, later)
+	probing := s.popDue(time.Now())
+	if n := s.expediteAll(); n != 3 {
+		t.Errorf("Expected 3 servers, got %d", n)
+	}
+	if due := s.popDue(time.Now()); len(due) != 1 || due[0].server.ID != 3 {
+		t.Errorf("Expected server 3 to be due right away, got %+v", due)
+	}
+	s.reschedule(probing[0].entry, later)
+	if due := s.popDue(time.Now()); len(due) != 1 || due[0].server.ID != 1 {
+		t.Errorf("Expected server 1 to be due right away, got %+v", due)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	interval := 10 * time.Second
+
+	for i := 0; i < 1000; i++ {
+		if d := jitter(interval, 0.1); d < 9*time.Second || d > 11*time.Second {
+			t.Fatalf("Jittered interval %v out of bounds", d)
+		}
+		if d := spread(interval, 0.1); d < 0 || d > time.Second {
+			t.Fatalf("Spread %v out of bounds", d)
+		}
+	}
+
+	if d := jitter(interval, 0); d != interval {
+		t.Errorf("Expected no jitter, got %v", d)
+	}
+	if d := spread(interval, 0); d != 0 {
+		t.Errorf("Expected no spread, got %v", d)
+	}
+}
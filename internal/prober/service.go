@@ -1,7 +1,9 @@
 package prober
 
 import (
+	"context"
 	"game-server-monitor/internal/database"
+	"game-server-monitor/internal/events"
 	"game-server-monitor/internal/models"
 	"log"
 	"time"
@@ -13,9 +15,10 @@ type ProberService struct {
 	dbService        *database.DatabaseService
 }
 
-// NewProberService creates a new ProberService with default configuration
+// NewProberService creates a new ProberService with the default
+// configuration and overrides from the environment
 func NewProberService(dbService *database.DatabaseService) *ProberService {
-	config := DefaultBackgroundProberConfig()
+	config := LoadBackgroundProberConfig()
 	backgroundProber := NewBackgroundProber(dbService, config)
 
 	return &ProberService{
@@ -51,6 +54,27 @@ func (ps *ProberService) IsRunning() bool {
 	return ps.backgroundProber.IsRunning()
 }
 
+// EventBus returns the bus on which server state changes are published
+func (ps *ProberService) EventBus() *events.Bus {
+	return ps.backgroundProber.GetEventBus()
+}
+
+// Subscribe registers a handler for server state-change events of the given
+// types (all types when none are given) and returns an unsubscribe function
+func (ps *ProberService) Subscribe(name string, handler events.Handler, types ...events.Type) func() {
+	return ps.backgroundProber.GetEventBus().Subscribe(name, handler, types...)
+}
+
+// AddCycleHook registers a function to run after every probe cycle
+func (ps *ProberService) AddCycleHook(hook CycleHook) {
+	ps.backgroundProber.AddCycleHook(hook)
+}
+
+// AddProbeHook registers a function to run after every stored probe result
+func (ps *ProberService) AddProbeHook(hook ProbeHook) {
+	ps.backgroundProber.AddProbeHook(hook)
+}
+
 // GetServerStatus retrieves cached server status
 func (ps *ProberService) GetServerStatus(serverID uint) (*models.ServerStatus, bool) {
 	return ps.backgroundProber.GetServerStatus(serverID)
@@ -105,9 +129,47 @@ func (ps *ProberService) GetAllServersWithStatus() (*models.ServerListResponse,
 	}, nil
 }
 
-// ForceProbeServer immediately probes a specific server
-func (ps *ProberService) ForceProbeServer(serverID uint) (*models.ServerStatus, error) {
-	return ps.backgroundProber.ForceProbeServer(serverID)
+// ForceProbeServer immediately probes a specific server, giving up when ctx is done
+func (ps *ProberService) ForceProbeServer(ctx context.Context, serverID uint) (*models.ServerStatus, error) {
+	return ps.backgroundProber.ForceProbeServer(ctx, serverID)
+}
+
+// ServerCreated probes a newly created server right away, in the background
+func (ps *ProberService) ServerCreated(server *models.Server) {
+	ps.backgroundProber.UpdateServer(server, true)
+}
+
+// ServerUpdated applies a changed server configuration. If the probe target
+// changed (or the previous configuration is unknown) the cached status is
+// discarded and the server is probed right away, in the background.
+func (ps *ProberService) ServerUpdated(previous, server *models.Server) {
+	ps.backgroundProber.UpdateServer(server, previous == nil || probeTargetChanged(previous, server))
+}
+
+// ServerDeleted stops probing a deleted server and drops its cached status
+func (ps *ProberService) ServerDeleted(serverID uint) {
+	ps.backgroundProber.RemoveServer(serverID)
+}
+
+// Pause stops background probing until Resume is called
+func (ps *ProberService) Pause() {
+	ps.backgroundProber.Pause()
+}
+
+// Resume continues background probing
+func (ps *ProberService) Resume() {
+	ps.backgroundProber.Resume()
+}
+
+// IsPaused returns whether background probing is paused
+func (ps *ProberService) IsPaused() bool {
+	return ps.backgroundProber.IsPaused()
+}
+
+// ProbeAll probes every server right away, in the background, and returns
+// the number of servers
+func (ps *ProberService) ProbeAll() int {
+	return ps.backgroundProber.ProbeAll()
 }
 
 // SetProbeInterval updates the probe interval
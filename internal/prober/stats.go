@@ -0,0 +1,64 @@
+package prober
+
+import (
+	"sync"
+	"time"
+)
+
+// cycleStats summarizes the probes the loop ran during one cycle, the time
+// between two schedule syncs
+type cycleStats struct {
+	start    time.Time // When the first probe of the cycle started
+	end      time.Time // When the last probe of the cycle finished
+	probes   int
+	failures int // Probes that found the server offline
+}
+
+// probeStats counts the probes run by the loop, in total and per cycle
+type probeStats struct {
+	current  cycleStats
+	last     cycleStats
+	probes   int
+	failures int
+	mutex    sync.Mutex
+}
+
+// record counts a finished probe
+func (s *probeStats) record(start, end time.Time, online bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.current.probes == 0 || start.Before(s.current.start) {
+		s.current.start = start
+	}
+	if end.After(s.current.end) {
+		s.current.end = end
+	}
+	s.current.probes++
+	s.probes++
+	if !online {
+		s.current.failures++
+		s.failures++
+	}
+}
+
+// endCycle completes the current cycle and starts a new one
+func (s *probeStats) endCycle() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.last = s.current
+	s.current = cycleStats{}
+}
+
+// addTo adds the counters to a stats map
+func (s *probeStats) addTo(stats map[string]interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats["total_probes"] = s.probes
+	stats["total_failures"] = s.failures
+	stats["last_cycle_probes"] = s.last.probes
+	stats["last_cycle_failures"] = s.last.failures
+	stats["last_cycle_duration"] = s.last.end.Sub(s.last.start).String()
+}
@@ -0,0 +1,32 @@
+package prober
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProbeStats(t *testing.T) {
+	var stats probeStats
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	stats.record(start.Add(time.Second), start.Add(3*time.Second), true)
+	stats.record(start, start.Add(2*time.Second), false)
+	stats.endCycle()
+	stats.record(start.Add(time.Minute), start.Add(time.Minute+time.Second), false)
+
+	values := make(map[string]interface{})
+	stats.addTo(values)
+
+	expected := map[string]interface{}{
+		"total_probes":        3,
+		"total_failures":      2,
+		"last_cycle_probes":   2,
+		"last_cycle_failures": 1,
+		"last_cycle_duration": "3s",
+	}
+	for key, want := range expected {
+		if values[key] != want {
+			t.Errorf("Expected %s to be %v, got %v", key, want, values[key])
+		}
+	}
+}
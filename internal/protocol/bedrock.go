@@ -0,0 +1,179 @@
+package protocol
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"game-server-monitor/internal/models"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RakNet packet IDs used by the Bedrock status query
+const (
+	raknetUnconnectedPing = 0x01
+	raknetUnconnectedPong = 0x1c
+)
+
+// raknetMagic identifies offline RakNet messages
+var raknetMagic = []byte{
+	0x00, 0xff, 0xff, 0x00, 0xfe, 0xfe, 0xfe, 0xfe,
+	0xfd, 0xfd, 0xfd, 0xfd, 0x12, 0x34, 0x56, 0x78,
+}
+
+// BedrockStatus is the server information advertised in a RakNet unconnected pong
+type BedrockStatus struct {
+	Edition         string // MCPE, or MCEE for Education Edition
+	MOTD            string
+	ProtocolVersion int
+	Version         string
+	Players         int
+	MaxPlayers      int
+	ServerID        string
+	LevelName       string // Second MOTD line
+	GameMode        string
+	GameModeID      int
+	PortIPv4        int
+	PortIPv6        int
+}
+
+func init() {
+	Register(ServerType{
+		Name:         "minecraft_bedrock",
+		DisplayName:  "Minecraft Bedrock",
+		DefaultPort:  19132,
+		Transport:    "udp",
+		Capabilities: []string{CapabilityPlayers, CapabilityVersion, CapabilityMOTD, CapabilityGameMode},
+		Prober:       ProberFunc(ProbeMinecraftBedrock),
+	})
+}
+
+// ProbeMinecraftBedrock probes a Minecraft Bedrock Edition server with a RakNet unconnected ping
+func ProbeMinecraftBedrock(ctx context.Context, server *models.Server) (*models.ServerStatus, error) {
+	ip, err := ResolveHost(ctx, server.Address, server.Family)
+	if err != nil {
+		return nil, err
+	}
+
+	address := net.JoinHostPort(ip.String(), strconv.Itoa(server.Port))
+	response, ping, err := QueryBedrock(ctx, ip.String(), server.Port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Minecraft Bedrock server %s: %w", address, err)
+	}
+
+	serverStatus := &models.ServerStatus{
+		Online:      true,
+		Players:     response.Players,
+		MaxPlayers:  response.MaxPlayers,
+		Version:     response.Version,
+		Ping:        ping.Milliseconds(),
+		MOTD:        response.MOTD,
+		GameMode:    response.GameMode,
+		Protocol:    response.ProtocolVersion,
+		LastUpdated: time.Now(),
+		Bedrock: &models.BedrockInfo{
+			Edition:   response.Edition,
+			LevelName: response.LevelName,
+			PortIPv4:  response.PortIPv4,
+			PortIPv6:  response.PortIPv6,
+		},
+
+		ResolvedAddress: address,
+	}
+
+	return serverStatus, nil
+}
+
+// QueryBedrock sends a RakNet unconnected ping to a Bedrock server and
+// returns the parsed pong and the round trip time
+func QueryBedrock(ctx context.Context, address string, port int) (*BedrockStatus, time.Duration, error) {
+	conn, err := dial(ctx, "udp", net.JoinHostPort(address, strconv.Itoa(port)))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+	defer bindConn(ctx, conn)()
+
+	var guid [8]byte
+	if _, err := rand.Read(guid[:]); err != nil {
+		return nil, 0, err
+	}
+
+	startTime := time.Now()
+	ping := make([]byte, 0, 33)
+	ping = append(ping, raknetUnconnectedPing)
+	ping = binary.BigEndian.AppendUint64(ping, uint64(startTime.UnixMilli()))
+	ping = append(ping, raknetMagic...)
+	ping = append(ping, guid[:]...)
+
+	if _, err := conn.Write(ping); err != nil {
+		return nil, 0, err
+	}
+
+	buffer := make([]byte, 1500)
+	n, err := conn.Read(buffer)
+	if err != nil {
+		return nil, 0, err
+	}
+	rtt := time.Since(startTime)
+
+	status, err := parseBedrockPong(buffer[:n])
+	if err != nil {
+		return nil, 0, err
+	}
+	return status, rtt, nil
+}
+
+// parseBedrockPong parses a RakNet unconnected pong:
+// ID, time (8), server GUID (8), magic (16), string length (2), server ID string
+func parseBedrockPong(data []byte) (*BedrockStatus, error) {
+	const header = 1 + 8 + 8 + 16 + 2
+	if len(data) < header || data[0] != raknetUnconnectedPong {
+		return nil, errors.New("invalid unconnected pong")
+	}
+	if !bytes.Equal(data[17:33], raknetMagic) {
+		return nil, errors.New("invalid RakNet magic in pong")
+	}
+
+	length := int(binary.BigEndian.Uint16(data[33:35]))
+	if len(data) < header+length {
+		return nil, errors.New("truncated unconnected pong")
+	}
+
+	// MCPE;MOTD;protocol;version;players;max;server ID;level name;game mode;game mode ID;port v4;port v6;
+	fields := strings.Split(string(data[header:header+length]), ";")
+	if len(fields) < 6 {
+		return nil, fmt.Errorf("unexpected server ID string with %d fields", len(fields))
+	}
+
+	field := func(i int) string {
+		if i < len(fields) {
+			return fields[i]
+		}
+		return ""
+	}
+	number := func(i int) int {
+		n, _ := strconv.Atoi(field(i))
+		return n
+	}
+
+	return &BedrockStatus{
+		Edition:         field(0),
+		MOTD:            field(1),
+		ProtocolVersion: number(2),
+		Version:         field(3),
+		Players:         number(4),
+		MaxPlayers:      number(5),
+		ServerID:        field(6),
+		LevelName:       field(7),
+		GameMode:        field(8),
+		GameModeID:      number(9),
+		PortIPv4:        number(10),
+		PortIPv6:        number(11),
+	}, nil
+}
@@ -0,0 +1,133 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"game-server-monitor/internal/models"
+	"net"
+	"testing"
+	"time"
+)
+
+// startBedrockResponder answers RakNet unconnected pings on a local UDP port
+// with the given server ID string and returns the port
+func startBedrockResponder(t *testing.T, serverID string) int {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Failed to listen:", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			ping := buffer[:n]
+			if n != 33 || ping[0] != raknetUnconnectedPing || !bytes.Equal(ping[9:25], raknetMagic) {
+				t.Errorf("Unexpected ping packet: %x", ping)
+				continue
+			}
+
+			pong := []byte{raknetUnconnectedPong}
+			pong = append(pong, ping[1:9]...) // Echo the client time
+			pong = binary.BigEndian.AppendUint64(pong, 0x1122334455667788)
+			pong = append(pong, raknetMagic...)
+			pong = binary.BigEndian.AppendUint16(pong, uint16(len(serverID)))
+			pong = append(pong, serverID...)
+			conn.WriteTo(pong, addr)
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func TestQueryBedrock(t *testing.T) {
+	port := startBedrockResponder(t,
+		"MCPE;Dedicated Server;686;1.21.2;3;10;13253860892328930865;Bedrock level;Survival;1;19132;19133;")
+
+	status, _, err := QueryBedrock(withTimeout(t, time.Second), "127.0.0.1", port)
+	if err != nil {
+		t.Fatal("Query failed:", err)
+	}
+
+	expected := BedrockStatus{
+		Edition:         "MCPE",
+		MOTD:            "Dedicated Server",
+		ProtocolVersion: 686,
+		Version:         "1.21.2",
+		Players:         3,
+		MaxPlayers:      10,
+		ServerID:        "13253860892328930865",
+		LevelName:       "Bedrock level",
+		GameMode:        "Survival",
+		GameModeID:      1,
+		PortIPv4:        19132,
+		PortIPv6:        19133,
+	}
+	if *status != expected {
+		t.Errorf("Expected %+v, got %+v", expected, *status)
+	}
+}
+
+func TestProbeMinecraftBedrock(t *testing.T) {
+	// Older servers only send the first six fields
+	port := startBedrockResponder(t, "MCPE;Geyser;390;1.14.60;0;20")
+
+	status, err := ProbeMinecraftBedrock(withTimeout(t, time.Second), &models.Server{Address: "127.0.0.1", Port: port})
+	if err != nil {
+		t.Fatal("Probe failed:", err)
+	}
+
+	if !status.Online || status.Players != 0 || status.MaxPlayers != 20 || status.Version != "1.14.60" ||
+		status.MOTD != "Geyser" || status.Protocol != 390 {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+	if status.Bedrock == nil || status.Bedrock.Edition != "MCPE" || status.Bedrock.PortIPv4 != 0 {
+		t.Errorf("Unexpected Bedrock details: %+v", status.Bedrock)
+	}
+}
+
+func TestProbeMinecraftBedrock_Details(t *testing.T) {
+	port := startBedrockResponder(t, "MCEE;Classroom;390;1.14.60;2;20;12345;Lesson World;Survival;1;19132;19133;")
+
+	status, err := ProbeMinecraftBedrock(withTimeout(t, time.Second), &models.Server{Address: "127.0.0.1", Port: port})
+	if err != nil {
+		t.Fatal("Probe failed:", err)
+	}
+
+	want := models.BedrockInfo{Edition: "MCEE", LevelName: "Lesson World", PortIPv4: 19132, PortIPv6: 19133}
+	if status.Bedrock == nil || *status.Bedrock != want {
+		t.Errorf("Expected Bedrock details %+v, got %+v", want, status.Bedrock)
+	}
+	if status.GameMode != "Survival" {
+		t.Errorf("Unexpected game mode %q", status.GameMode)
+	}
+}
+
+func TestProbeMinecraftBedrock_NoResponse(t *testing.T) {
+	// A socket that never answers
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Failed to listen:", err)
+	}
+	defer conn.Close()
+
+	if _, err := ProbeMinecraftBedrock(withTimeout(t, 100*time.Millisecond), &models.Server{Address: "127.0.0.1", Port: conn.LocalAddr().(*net.UDPAddr).Port}); err == nil {
+		t.Error("Expected probe of silent server to fail")
+	}
+}
+
+func TestParseBedrockPong_Invalid(t *testing.T) {
+	if _, err := parseBedrockPong([]byte{raknetUnconnectedPong, 0x00}); err == nil {
+		t.Error("Expected short pong to be rejected")
+	}
+
+	pong := make([]byte, 35)
+	pong[0] = raknetUnconnectedPong
+	if _, err := parseBedrockPong(pong); err == nil {
+		t.Error("Expected pong without magic to be rejected")
+	}
+}
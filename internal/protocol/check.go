@@ -0,0 +1,299 @@
+package protocol
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"game-server-monitor/internal/models"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCheckResponse limits how much of a UDP response or HTTP body is matched
+const maxCheckResponse = 1 << 20
+
+// checkRootCAs overrides the system roots HTTPS certificates are verified
+// against, for tests
+var checkRootCAs *x509.CertPool
+
+// CertificateError is returned by HTTPS checks whose certificate failed
+// verification; TLS describes the certificate and why it was rejected
+type CertificateError struct {
+	TLS *models.TLSInfo
+	Err error
+}
+
+func (e *CertificateError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CertificateError) Unwrap() error {
+	return e.Err
+}
+
+func init() {
+	Register(ServerType{
+		Name:          "tcp",
+		DisplayName:   "TCP Port",
+		Transport:     "tcp",
+		Capabilities:  []string{},
+		Prober:        ProberFunc(ProbeTCP),
+		ValidateCheck: validateCheck,
+	})
+	Register(ServerType{
+		Name:          "udp",
+		DisplayName:   "UDP Service",
+		Transport:     "udp",
+		Capabilities:  []string{},
+		Prober:        ProberFunc(ProbeUDP),
+		ValidateCheck: validateCheck,
+	})
+	Register(ServerType{
+		Name:          "http",
+		DisplayName:   "HTTP",
+		DefaultPort:   80,
+		Transport:     "tcp",
+		Capabilities:  []string{},
+		Prober:        probeHTTP("http"),
+		ValidateCheck: validateCheck,
+	})
+	Register(ServerType{
+		Name:          "https",
+		DisplayName:   "HTTPS",
+		DefaultPort:   443,
+		Transport:     "tcp",
+		Capabilities:  []string{CapabilityTLS},
+		Prober:        probeHTTP("https"),
+		ValidateCheck: validateCheck,
+	})
+}
+
+// validateCheck validates the settings of the generic check types
+func validateCheck(check *models.HealthCheck) error {
+	if check.Path != "" && !strings.HasPrefix(check.Path, "/") {
+		return errors.New("invalid check path: must start with /")
+	}
+	if check.ExpectStatus != 0 && (check.ExpectStatus < 100 || check.ExpectStatus > 599) {
+		return errors.New("invalid expected status: must be between 100 and 599")
+	}
+	if _, err := hex.DecodeString(check.SendHex); err != nil {
+		return fmt.Errorf("invalid UDP payload: %w", err)
+	}
+	if _, err := regexp.Compile(check.ExpectRegex); err != nil {
+		return fmt.Errorf("invalid expect_regex: %w", err)
+	}
+	return nil
+}
+
+// ProbeTCP reports a server online if a TCP connection to its port can be established
+func ProbeTCP(ctx context.Context, server *models.Server) (*models.ServerStatus, error) {
+	address, err := ResolveAddress(ctx, server, server.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	conn, err := dial(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	ping := time.Since(start)
+	conn.Close()
+
+	return &models.ServerStatus{
+		Online:      true,
+		Ping:        ping.Milliseconds(),
+		LastUpdated: time.Now(),
+
+		ResolvedAddress: address,
+	}, nil
+}
+
+// ProbeUDP sends the check payload to a server and reports it online if it
+// answers with a response matching the check's expectations
+func ProbeUDP(ctx context.Context, server *models.Server) (*models.ServerStatus, error) {
+	payload, err := hex.DecodeString(server.Check.SendHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UDP payload: %w", err)
+	}
+
+	address, err := ResolveAddress(ctx, server, server.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dial(ctx, "udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	defer conn.Close()
+	defer bindConn(ctx, conn)()
+
+	start := time.Now()
+	if _, err := conn.Write(payload); err != nil {
+		return nil, fmt.Errorf("failed to send to %s: %w", address, err)
+	}
+
+	response := make([]byte, 65535)
+	n, err := conn.Read(response)
+	if err != nil {
+		return nil, fmt.Errorf("no response from %s: %w", address, err)
+	}
+	ping := time.Since(start)
+
+	if err := matchResponse(&server.Check, response[:n]); err != nil {
+		return nil, fmt.Errorf("unexpected response from %s: %w", address, err)
+	}
+
+	return &models.ServerStatus{
+		Online:      true,
+		Ping:        ping.Milliseconds(),
+		LastUpdated: time.Now(),
+
+		ResolvedAddress: address,
+	}, nil
+}
+
+// probeHTTP returns a prober that requests the check path over the given
+// scheme. Redirects are not followed, so a redirect status can be expected.
+func probeHTTP(scheme string) ProberFunc {
+	return func(ctx context.Context, server *models.Server) (*models.ServerStatus, error) {
+		address, err := ResolveAddress(ctx, server, server.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		path := server.Check.Path
+		if path == "" {
+			path = "/"
+		}
+		url := scheme + "://" + net.JoinHostPort(server.Address, strconv.Itoa(server.Port)) + path
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid check URL %s: %w", url, err)
+		}
+
+		// The certificate is verified in VerifyConnection instead of by the
+		// handshake, so that it is known even if it is expired or untrusted
+		var certificate *models.TLSInfo
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: true,
+			VerifyConnection: func(state tls.ConnectionState) error {
+				if len(state.PeerCertificates) == 0 {
+					return errors.New("server sent no certificate")
+				}
+				certificate = tlsInfo(state.PeerCertificates[0])
+				if err := verifyCertificate(state, server.Address); err != nil {
+					certificate.Error = err.Error()
+					return err
+				}
+				return nil
+			},
+		}
+
+		// Connect to the resolved address, so dual-stack servers are checked
+		// per family, while the URL keeps the host for Host header and SNI
+		client := &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+					return dial(ctx, network, address)
+				},
+				TLSClientConfig:   tlsConfig,
+				DisableKeepAlives: true,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+
+		start := time.Now()
+		resp, err := client.Do(request)
+		if err != nil {
+			err = fmt.Errorf("failed to request %s: %w", url, err)
+			if certificate != nil && certificate.Error != "" {
+				return nil, &CertificateError{TLS: certificate, Err: err}
+			}
+			return nil, err
+		}
+		defer resp.Body.Close()
+		ping := time.Since(start)
+
+		if expected := server.Check.ExpectStatus; (expected == 0 && resp.StatusCode >= 400) || (expected != 0 && resp.StatusCode != expected) {
+			return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+		}
+
+		if server.Check.Expect != "" || server.Check.ExpectRegex != "" {
+			body, err := io.ReadAll(io.LimitReader(resp.Body, maxCheckResponse))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read response from %s: %w", url, err)
+			}
+			if err := matchResponse(&server.Check, body); err != nil {
+				return nil, fmt.Errorf("unexpected response from %s: %w", url, err)
+			}
+		}
+
+		status := &models.ServerStatus{
+			Online:      true,
+			Ping:        ping.Milliseconds(),
+			HTTPStatus:  resp.StatusCode,
+			LastUpdated: time.Now(),
+
+			ResolvedAddress: address,
+			TLS:             certificate,
+		}
+		return status, nil
+	}
+}
+
+// verifyCertificate verifies the certificate chain of a TLS connection for
+// a host name or IP, as the handshake would
+func verifyCertificate(state tls.ConnectionState, host string) error {
+	options := x509.VerifyOptions{
+		Roots:         checkRootCAs,
+		DNSName:       host,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range state.PeerCertificates[1:] {
+		options.Intermediates.AddCert(cert)
+	}
+	_, err := state.PeerCertificates[0].Verify(options)
+	return err
+}
+
+// tlsInfo summarizes a server certificate
+func tlsInfo(cert *x509.Certificate) *models.TLSInfo {
+	return &models.TLSInfo{
+		Subject:       cert.Subject.CommonName,
+		Issuer:        cert.Issuer.CommonName,
+		NotAfter:      cert.NotAfter,
+		DaysRemaining: int(math.Floor(time.Until(cert.NotAfter).Hours() / 24)),
+	}
+}
+
+// matchResponse checks a response against the check's expected substring and regular expression
+func matchResponse(check *models.HealthCheck, response []byte) error {
+	if check.Expect != "" && !bytes.Contains(response, []byte(check.Expect)) {
+		return fmt.Errorf("response does not contain %q", check.Expect)
+	}
+	if check.ExpectRegex != "" {
+		re, err := regexp.Compile(check.ExpectRegex)
+		if err != nil {
+			return fmt.Errorf("invalid expect_regex: %w", err)
+		}
+		if !re.Match(response) {
+			return fmt.Errorf("response does not match %q", check.ExpectRegex)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package protocol
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"game-server-monitor/internal/models"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// splitHostPort returns the host and port of a listener address
+func splitHostPort(t *testing.T, address string) (string, int) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		t.Fatal("Invalid address:", err)
+	}
+	port, _ := strconv.Atoi(portStr)
+	return host, port
+}
+
+func TestProbeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Failed to listen:", err)
+	}
+	host, port := splitHostPort(t, listener.Addr().String())
+
+	status, err := ProbeTCP(withTimeout(t, time.Second), &models.Server{Address: host, Port: port})
+	if err != nil || !status.Online {
+		t.Fatalf("Expected online status, got %+v (%v)", status, err)
+	}
+	if status.Players != 0 || status.MaxPlayers != 0 {
+		t.Errorf("Expected no player counts, got %d/%d", status.Players, status.MaxPlayers)
+	}
+
+	listener.Close()
+	if _, err := ProbeTCP(withTimeout(t, time.Second), &models.Server{Address: host, Port: port}); err == nil {
+		t.Error("Expected an error for a closed port")
+	}
+}
+
+func TestProbeUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Failed to listen:", err)
+	}
+	defer conn.Close()
+
+	// Answer "ping" with "pong" and ignore anything else
+	go func() {
+		buffer := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			if string(buffer[:n]) == "ping" {
+				conn.WriteTo([]byte("pong v1.2"), addr)
+			}
+		}
+	}()
+	host, port := splitHostPort(t, conn.LocalAddr().String())
+
+	tests := []struct {
+		name   string
+		check  models.HealthCheck
+		online bool
+	}{
+		{"any response", models.HealthCheck{SendHex: "70696e67"}, true},
+		{"substring", models.HealthCheck{SendHex: "70696e67", Expect: "pong"}, true},
+		{"regex", models.HealthCheck{SendHex: "70696e67", ExpectRegex: `v\d+\.\d+`}, true},
+		{"wrong substring", models.HealthCheck{SendHex: "70696e67", Expect: "hello"}, false},
+		{"no response", models.HealthCheck{SendHex: "6e6f7065"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := ProbeUDP(withTimeout(t, 200*time.Millisecond), &models.Server{Address: host, Port: port, Check: tt.check})
+			if tt.online && (err != nil || !status.Online) {
+				t.Errorf("Expected online status, got %+v (%v)", status, err)
+			}
+			if !tt.online && err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestProbeHTTP(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"ok","version":"2.4.1"}`)
+	})
+	handler.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/health", http.StatusMovedPermanently)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	host, port := splitHostPort(t, server.Listener.Addr().String())
+
+	tests := []struct {
+		name   string
+		check  models.HealthCheck
+		online bool
+	}{
+		{"default path", models.HealthCheck{}, false}, // 404
+		{"ok", models.HealthCheck{Path: "/health"}, true},
+		{"substring", models.HealthCheck{Path: "/health", Expect: `"status":"ok"`}, true},
+		{"regex", models.HealthCheck{Path: "/health", ExpectRegex: `"version":"2\.\d+`}, true},
+		{"regex mismatch", models.HealthCheck{Path: "/health", ExpectRegex: `"version":"3\.`}, false},
+		{"redirect expected", models.HealthCheck{Path: "/old", ExpectStatus: http.StatusMovedPermanently}, true},
+		{"wrong status", models.HealthCheck{Path: "/health", ExpectStatus: http.StatusNoContent}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := probeHTTP("http")(withTimeout(t, time.Second), &models.Server{Address: host, Port: port, Check: tt.check})
+			if tt.online && (err != nil || !status.Online) {
+				t.Errorf("Expected online status, got %+v (%v)", status, err)
+			}
+			if !tt.online && err == nil {
+				t.Errorf("Expected an error, got %+v", status)
+			}
+		})
+	}
+}
+
+func TestProbeHTTPS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+	host, port := splitHostPort(t, server.Listener.Addr().String())
+
+	// Untrusted certificates fail the check
+	if _, err := probeHTTP("https")(withTimeout(t, time.Second), &models.Server{Address: host, Port: port}); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("Expected a certificate error, got %v", err)
+	}
+
+	checkRootCAs = x509.NewCertPool()
+	checkRootCAs.AddCert(server.Certificate())
+	defer func() { checkRootCAs = nil }()
+
+	status, err := probeHTTP("https")(withTimeout(t, time.Second), &models.Server{Address: host, Port: port})
+	if err != nil || !status.Online {
+		t.Fatalf("Expected online status, got %+v (%v)", status, err)
+	}
+	if status.HTTPStatus != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", status.HTTPStatus)
+	}
+	if status.TLS == nil || !status.TLS.NotAfter.Equal(server.Certificate().NotAfter) {
+		t.Fatalf("Expected certificate details, got %+v", status.TLS)
+	}
+	if status.TLS.DaysRemaining <= 0 {
+		t.Errorf("Expected days remaining, got %d", status.TLS.DaysRemaining)
+	}
+}
+
+// startTLSServer starts an HTTPS server on 127.0.0.1 with a self-signed
+// certificate valid from notBefore to notAfter
+func startTLSServer(t *testing.T, notBefore, notAfter time.Time) (*httptest.Server, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal("Failed to generate key:", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "self-signed.test"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal("Failed to create certificate:", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server, cert
+}
+
+func TestProbeHTTPS_InvalidCertificate(t *testing.T) {
+	now := time.Now()
+
+	// Self-signed certificates are reported with the verification error
+	server, _ := startTLSServer(t, now.Add(-time.Hour), now.Add(30*24*time.Hour))
+	host, port := splitHostPort(t, server.Listener.Addr().String())
+
+	_, err := probeHTTP("https")(withTimeout(t, time.Second), &models.Server{Address: host, Port: port})
+	var certErr *CertificateError
+	if !errors.As(err, &certErr) {
+		t.Fatalf("Expected a certificate error, got %v", err)
+	}
+	if certErr.TLS.Subject != "self-signed.test" || certErr.TLS.DaysRemaining != 29 ||
+		!strings.Contains(certErr.TLS.Error, "unknown authority") {
+		t.Errorf("Unexpected certificate details: %+v", certErr.TLS)
+	}
+
+	// Expired certificates are reported even if trusted, with negative days remaining
+	server, cert := startTLSServer(t, now.Add(-30*24*time.Hour), now.Add(-50*time.Hour))
+	host, port = splitHostPort(t, server.Listener.Addr().String())
+
+	checkRootCAs = x509.NewCertPool()
+	checkRootCAs.AddCert(cert)
+	defer func() { checkRootCAs = nil }()
+
+	_, err = probeHTTP("https")(withTimeout(t, time.Second), &models.Server{Address: host, Port: port})
+	if !errors.As(err, &certErr) {
+		t.Fatalf("Expected a certificate error, got %v", err)
+	}
+	if !certErr.TLS.NotAfter.Equal(cert.NotAfter) || certErr.TLS.DaysRemaining != -3 ||
+		!strings.Contains(certErr.TLS.Error, "expired") {
+		t.Errorf("Unexpected certificate details: %+v", certErr.TLS)
+	}
+}
+
+func TestValidateCheck(t *testing.T) {
+	valid := []models.HealthCheck{
+		{},
+		{Path: "/health?full=1", ExpectStatus: 204},
+		{SendHex: "ffffffff54", ExpectRegex: `^\xff`},
+	}
+	for _, check := range valid {
+		if err := validateCheck(&check); err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", check, err)
+		}
+	}
+
+	invalid := []models.HealthCheck{
+		{Path: "health"},
+		{ExpectStatus: 42},
+		{SendHex: "xyz"},
+		{ExpectRegex: "("},
+	}
+	for _, check := range invalid {
+		if err := validateCheck(&check); err == nil {
+			t.Errorf("Expected %+v to be invalid", check)
+		}
+	}
+}
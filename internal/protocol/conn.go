@@ -0,0 +1,57 @@
+package protocol
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// defaultTimeout bounds network operations when the context has no deadline
+const defaultTimeout = 5 * time.Second
+
+// timeoutOf returns the time left until the deadline of ctx, for libraries
+// that take a timeout instead of a context
+func timeoutOf(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+	return defaultTimeout
+}
+
+// dial connects to an address, giving up when ctx is done
+func dial(ctx context.Context, network, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, address)
+}
+
+// bindConn bounds all I/O on conn by the deadline of ctx and interrupts
+// blocked reads and writes as soon as ctx is cancelled. Call the returned
+// function when done with the connection.
+func bindConn(ctx context.Context, conn net.Conn) (stop func() bool) {
+	conn.SetDeadline(time.Now().Add(timeoutOf(ctx)))
+	return context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Unix(1, 0))
+	})
+}
+
+// runWithContext runs fn, which must end on its own within the timeout of
+// ctx, and returns early with the error of ctx if ctx is done first
+func runWithContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := fn()
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
@@ -0,0 +1,69 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"game-server-monitor/internal/models"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// withTimeout returns a context that is done after the timeout or when the test ends
+func withTimeout(t *testing.T, timeout time.Duration) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestProbe_Cancel(t *testing.T) {
+	// A server that accepts connections but never answers
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Failed to listen:", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go io.Copy(io.Discard, conn)
+		}
+	}()
+	host, port := splitHostPort(t, listener.Addr().String())
+
+	// Cancelling interrupts the pending read long before the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	if _, err := ProbeMinecraft(ctx, &models.Server{Address: host, Port: port}); err == nil {
+		t.Error("Expected an error for a cancelled probe")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the probe to stop promptly, took %v", elapsed)
+	}
+}
+
+func TestRunWithContext(t *testing.T) {
+	value, err := runWithContext(context.Background(), func() (int, error) { return 42, nil })
+	if value != 42 || err != nil {
+		t.Errorf("Expected 42, got %d (%v)", value, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	release := make(chan struct{})
+	defer close(release)
+
+	_, err = runWithContext(ctx, func() (int, error) {
+		<-release
+		return 0, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package protocol
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"game-server-monitor/internal/models"
+	"log"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcstatus-io/mcutil"
+	"github.com/mcstatus-io/mcutil/description"
+	"github.com/mcstatus-io/mcutil/options"
+)
+
+func init() {
+	Register(ServerType{
+		Name:         "minecraft",
+		DisplayName:  "Minecraft",
+		DefaultPort:  25565,
+		Transport:    "tcp",
+		SRVService:   "minecraft",
+		Capabilities: []string{CapabilityPlayers, CapabilityPlayerList, CapabilityVersion, CapabilityMOTD, CapabilityIcon, CapabilityMods, CapabilityRCON},
+		Prober:       ProberFunc(ProbeMinecraft),
+
+		DefaultRCONPort: 25575,
+	})
+}
+
+// ProbeMinecraft probes a Minecraft Java Edition server with a Server List
+// Ping, and with a query for the full player list if EnableQuery is set
+func ProbeMinecraft(ctx context.Context, server *models.Server) (*models.ServerStatus, error) {
+	startTime := time.Now()
+
+	serverType, _ := Lookup("minecraft")
+	target, err := ResolveSRV(ctx, server, serverType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve Minecraft server %s: %w", server.Address, err)
+	}
+
+	// Query the server status
+	response, latency, err := QueryJava(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Minecraft server %s (%s): %w", server.Address, target.Address(), err)
+	}
+
+	// Calculate ping (use response latency if available, otherwise calculate from start time)
+	var ping int64
+	if latency > 0 {
+		ping = latency.Milliseconds()
+	} else {
+		ping = time.Since(startTime).Milliseconds()
+	}
+
+	playerList := make([]models.Player, 0, len(response.Players.Sample))
+	for _, sample := range response.Players.Sample {
+		playerList = append(playerList, models.Player{Name: cleanText(sample.Name), ID: sample.ID})
+	}
+
+	if server.EnableQuery {
+		queryPort := target.Port
+		if server.QueryPort > 0 {
+			queryPort = server.QueryPort
+		}
+		names, err := QueryMinecraftPlayers(ctx, target.IP, queryPort)
+		if err != nil {
+			log.Printf("Query of Minecraft server %s failed, using player sample: %v", net.JoinHostPort(target.IP.String(), strconv.Itoa(queryPort)), err)
+		} else {
+			playerList = mergePlayerNames(playerList, names)
+		}
+	}
+
+	// Parse the response
+	serverStatus := &models.ServerStatus{
+		Online:             true,
+		Players:            response.Players.Online,
+		MaxPlayers:         response.Players.Max,
+		Version:            cleanText(response.Version.Name),
+		Ping:               ping,
+		Protocol:           response.Version.Protocol,
+		EnforcesSecureChat: response.EnforcesSecureChat,
+		ResolvedAddress:    target.Address(),
+		SRVTarget:          target.SRV,
+		PlayerList:         playerList,
+		LastUpdated:        time.Now(),
+	}
+
+	if motd, err := description.ParseFormatting(response.Description, description.White); err != nil {
+		log.Printf("Failed to parse MOTD of Minecraft server %s: %v", target.Address(), err)
+	} else {
+		serverStatus.MOTD = strings.TrimSpace(motd.Clean)
+		serverStatus.MOTDFormatted = motd.Raw
+		serverStatus.MOTDHTML = motd.HTML
+	}
+
+	if response.Favicon != "" {
+		if icon, err := decodeFavicon(response.Favicon); err != nil {
+			log.Printf("Ignoring icon of Minecraft server %s: %v", target.Address(), err)
+		} else {
+			serverStatus.Icon = icon
+		}
+	}
+
+	serverStatus.ModLoader, serverStatus.Mods = javaMods(response)
+
+	return serverStatus, nil
+}
+
+// cleanText strips the formatting codes from a legacy text string
+func cleanText(text string) string {
+	formatting, err := description.ParseFormatting(text)
+	if err != nil {
+		return text
+	}
+	return formatting.Clean
+}
+
+// decodeFavicon decodes the data URI of a server icon into PNG data
+func decodeFavicon(favicon string) ([]byte, error) {
+	const prefix = "data:image/png;base64,"
+	if !strings.HasPrefix(favicon, prefix) {
+		return nil, errors.New("icon is not a PNG data URI")
+	}
+
+	// Some servers wrap the base64 data across lines
+	data := strings.NewReplacer("\n", "", "\r", "").Replace(favicon[len(prefix):])
+	icon, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid icon data: %w", err)
+	}
+	if !bytes.HasPrefix(icon, pngSignature) {
+		return nil, errors.New("icon is not a PNG image")
+	}
+	return icon, nil
+}
+
+// pngSignature starts every PNG file
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// javaMods returns the mod loader and mods a modded server advertises
+func javaMods(response *JavaStatus) (string, []models.Mod) {
+	switch {
+	case response.ForgeData != nil:
+		loader := fmt.Sprintf("FML%d", response.ForgeData.FMLNetworkVersion)
+		if response.ForgeData.D != "" {
+			mods, err := decodeForgeMods(response.ForgeData.D)
+			if err != nil {
+				log.Printf("Failed to decode Forge mod list: %v", err)
+			}
+			return loader, mods
+		}
+
+		mods := make([]models.Mod, 0, len(response.ForgeData.Mods))
+		for _, mod := range response.ForgeData.Mods {
+			mods = append(mods, models.Mod{ID: mod.ModID, Version: mod.ModMarker})
+		}
+		return loader, mods
+
+	case response.ModInfo != nil:
+		mods := make([]models.Mod, 0, len(response.ModInfo.ModList))
+		for _, mod := range response.ModInfo.ModList {
+			mods = append(mods, models.Mod{ID: mod.ModID, Version: mod.Version})
+		}
+		return response.ModInfo.Type, mods
+	}
+	return "", nil
+}
+
+// decodeForgeMods decodes the compact forgeData.d mod list of Forge 1.18+
+// and NeoForge. The string packs 15 bits into every character, the first two
+// holding the byte length of the buffer. The buffer holds a truncation flag,
+// the mod count and per mod its channel count, ID, version and channels.
+func decodeForgeMods(d string) ([]models.Mod, error) {
+	chars := []rune(d)
+	if len(chars) < 2 {
+		return nil, errors.New("forge data too short")
+	}
+	size := int(chars[0]&0x7fff) | int(chars[1]&0x7fff)<<15
+
+	data := make([]byte, 0, size)
+	var buffer uint32
+	var bits uint
+	for _, c := range chars[2:] {
+		buffer |= uint32(c&0x7fff) << bits
+		bits += 15
+		for bits >= 8 && len(data) < size {
+			data = append(data, byte(buffer))
+			buffer >>= 8
+			bits -= 8
+		}
+	}
+	if len(data) < size {
+		return nil, errors.New("forge data truncated")
+	}
+
+	r := bytes.NewReader(data)
+	if _, err := r.ReadByte(); err != nil { // Truncated flag
+		return nil, err
+	}
+	var count uint16
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, err
+	}
+
+	mods := make([]models.Mod, 0, count)
+	for i := 0; i < int(count); i++ {
+		flags, err := readVarInt(r)
+		if err != nil {
+			return mods, err
+		}
+		channels := int(flags >> 1)
+
+		var mod models.Mod
+		if mod.ID, err = readString(r); err != nil {
+			return mods, err
+		}
+		// Server-only mods do not advertise a version
+		if flags&1 == 0 {
+			if mod.Version, err = readString(r); err != nil {
+				return mods, err
+			}
+		}
+		for j := 0; j < channels; j++ {
+			if _, err := readString(r); err != nil { // Name
+				return mods, err
+			}
+			if _, err := readString(r); err != nil { // Version
+				return mods, err
+			}
+			if _, err := r.ReadByte(); err != nil { // Required
+				return mods, err
+			}
+		}
+		mods = append(mods, mod)
+	}
+	return mods, nil
+}
+
+// QueryMinecraftPlayers fetches the names of all online players with a
+// GameSpy4 full stat query, which servers answer when enable-query is on.
+// mcutil takes no context, so the query is abandoned when ctx is done and
+// ends on its own once its timeout passes.
+func QueryMinecraftPlayers(ctx context.Context, ip net.IP, port int) ([]string, error) {
+	// mcutil joins host and port with "%s:%d", so IPv6 literals need their brackets
+	host := ip.String()
+	if ip.To4() == nil {
+		host = "[" + host + "]"
+	}
+
+	query := options.Query{
+		Timeout:   timeoutOf(ctx),
+		SessionID: rand.Int31(),
+	}
+	return runWithContext(ctx, func() ([]string, error) {
+		response, err := mcutil.FullQuery(host, uint16(port), query)
+		if err != nil {
+			return nil, err
+		}
+		return response.Players, nil
+	})
+}
+
+// mergePlayerNames builds the player list from the full list of names,
+// keeping the UUIDs known from the status sample
+func mergePlayerNames(sample []models.Player, names []string) []models.Player {
+	ids := make(map[string]string, len(sample))
+	for _, player := range sample {
+		ids[player.Name] = player.ID
+	}
+
+	players := make([]models.Player, 0, len(names))
+	for _, name := range names {
+		players = append(players, models.Player{Name: name, ID: ids[name]})
+	}
+	return players
+}
@@ -0,0 +1,255 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"game-server-monitor/internal/models"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// startQueryResponder answers GameSpy4 handshakes and full stat requests on
+// a UDP port of the given loopback IP with the given player names and
+// returns the port
+func startQueryResponder(t *testing.T, ip string, players []string) int {
+	conn, err := net.ListenPacket("udp", net.JoinHostPort(ip, "0"))
+	if err != nil {
+		t.Skip("Cannot listen on", ip, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			if n < 7 || buffer[0] != 0xfe || buffer[1] != 0xfd {
+				t.Errorf("Unexpected query packet: %x", buffer[:n])
+				continue
+			}
+			sessionID := buffer[3:7]
+
+			switch buffer[2] {
+			case 0x09: // Handshake
+				response := append([]byte{0x09}, sessionID...)
+				response = append(response, "9513307\x00"...)
+				conn.WriteTo(response, addr)
+			case 0x00: // Full stat
+				if n != 15 || binary.BigEndian.Uint32(buffer[7:11]) != 9513307 {
+					t.Errorf("Unexpected full stat request: %x", buffer[:n])
+					continue
+				}
+				response := append([]byte{0x00}, sessionID...)
+				response = append(response, "splitnum\x00\x80\x00"...)
+				response = append(response, "hostname\x00A Minecraft Server\x00numplayers\x002\x00\x00"...)
+				response = append(response, "\x01player_\x00\x00"...)
+				for _, name := range players {
+					response = append(response, name+"\x00"...)
+				}
+				response = append(response, 0x00)
+				conn.WriteTo(response, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func TestQueryMinecraftPlayers(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "::1"} {
+		t.Run(ip, func(t *testing.T) {
+			port := startQueryResponder(t, ip, []string{"Alice", "Bob"})
+
+			names, err := QueryMinecraftPlayers(withTimeout(t, time.Second), net.ParseIP(ip), port)
+			if err != nil {
+				t.Fatal("Query failed:", err)
+			}
+			if len(names) != 2 || names[0] != "Alice" || names[1] != "Bob" {
+				t.Errorf("Expected [Alice Bob], got %v", names)
+			}
+		})
+	}
+}
+
+func TestMergePlayerNames(t *testing.T) {
+	sample := []models.Player{{Name: "Alice", ID: "0d4d5b4a-3f8c-4b1d-9c8e-1f2a3b4c5d6e"}}
+
+	players := mergePlayerNames(sample, []string{"Alice", "Bob"})
+	if len(players) != 2 {
+		t.Fatalf("Expected 2 players, got %d", len(players))
+	}
+	if players[0].ID != sample[0].ID || players[1].Name != "Bob" || players[1].ID != "" {
+		t.Errorf("Unexpected players: %+v", players)
+	}
+}
+
+// startStatusResponder answers Server List Pings on a TCP port of the given
+// loopback IP with the given status JSON and returns the port
+func startStatusResponder(t *testing.T, ip, status string) int {
+	listener, err := net.Listen("tcp", net.JoinHostPort(ip, "0"))
+	if err != nil {
+		t.Skip("Cannot listen on", ip, err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+
+				if _, err := readPacket(reader, slpHandshake); err != nil {
+					t.Errorf("Failed to read handshake: %v", err)
+					return
+				}
+				if _, err := readPacket(reader, slpStatus); err != nil {
+					t.Errorf("Failed to read status request: %v", err)
+					return
+				}
+
+				var payload, response bytes.Buffer
+				writeVarInt(&payload, slpStatus)
+				writeString(&payload, status)
+				writePacket(&response, payload.Bytes())
+				conn.Write(response.Bytes())
+
+				ping, err := readPacket(reader, slpPing)
+				if err != nil {
+					return
+				}
+				response.Reset()
+				writePacket(&response, append([]byte{slpPing}, ping...))
+				conn.Write(response.Bytes())
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestProbeMinecraft(t *testing.T) {
+	icon := append([]byte(nil), pngSignature...)
+	icon = append(icon, "icon data"...)
+
+	port := startStatusResponder(t, "127.0.0.1", `{
+		"version": {"name": "Paper 1.20.4", "protocol": 765},
+		"players": {"max": 20, "online": 2, "sample": [{"name": "Alice", "id": "0d4d5b4a-3f8c-4b1d-9c8e-1f2a3b4c5d6e"}]},
+		"description": {"text": "Hello ", "extra": [{"text": "world", "color": "gold", "bold": true}]},
+		"favicon": "data:image/png;base64,`+base64.StdEncoding.EncodeToString(icon)+`",
+		"enforcesSecureChat": true,
+		"forgeData": {"fmlNetworkVersion": 2, "mods": [{"modId": "forge", "modmarker": "36.2.39"}]}
+	}`)
+
+	status, err := ProbeMinecraft(withTimeout(t, time.Second), &models.Server{Address: "127.0.0.1", Port: port})
+	if err != nil {
+		t.Fatal("Probe failed:", err)
+	}
+
+	if !status.Online || status.Players != 2 || status.MaxPlayers != 20 || status.Version != "Paper 1.20.4" || status.Protocol != 765 {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+	if status.MOTD != "Hello world" || status.MOTDFormatted != "Hello \u00a76\u00a7lworld" {
+		t.Errorf("Unexpected MOTD %q / %q", status.MOTD, status.MOTDFormatted)
+	}
+	if status.MOTDHTML == "" {
+		t.Error("Expected an HTML MOTD")
+	}
+	if !bytes.Equal(status.Icon, icon) {
+		t.Errorf("Unexpected icon %q", status.Icon)
+	}
+	if status.EnforcesSecureChat == nil || !*status.EnforcesSecureChat {
+		t.Error("Expected enforcesSecureChat to be set")
+	}
+	if status.ModLoader != "FML2" || len(status.Mods) != 1 || status.Mods[0] != (models.Mod{ID: "forge", Version: "36.2.39"}) {
+		t.Errorf("Unexpected mods %s %+v", status.ModLoader, status.Mods)
+	}
+	if len(status.PlayerList) != 1 || status.PlayerList[0].Name != "Alice" {
+		t.Errorf("Unexpected players: %+v", status.PlayerList)
+	}
+}
+
+// encodeForgeMods packs a Forge 1.18+ mod list the way the server does
+func encodeForgeMods(mods []models.Mod) string {
+	var data bytes.Buffer
+	data.WriteByte(0) // Not truncated
+	binary.Write(&data, binary.BigEndian, uint16(len(mods)))
+	for _, mod := range mods {
+		if mod.Version == "" {
+			writeVarInt(&data, 1<<1|1) // One channel, server-only
+			writeString(&data, mod.ID)
+		} else {
+			writeVarInt(&data, 1<<1)
+			writeString(&data, mod.ID)
+			writeString(&data, mod.Version)
+		}
+		writeString(&data, "main")
+		writeString(&data, "1")
+		data.WriteByte(1)
+	}
+	writeVarInt(&data, 0) // Non-mod channels
+
+	size := data.Len()
+	chars := []rune{rune(size & 0x7fff), rune(size >> 15 & 0x7fff)}
+	var buffer uint32
+	var bits uint
+	for _, b := range data.Bytes() {
+		if bits >= 15 {
+			chars = append(chars, rune(buffer&0x7fff))
+			buffer >>= 15
+			bits -= 15
+		}
+		buffer |= uint32(b) << bits
+		bits += 8
+	}
+	if bits > 0 {
+		chars = append(chars, rune(buffer&0x7fff))
+	}
+	return string(chars)
+}
+
+func TestDecodeForgeMods(t *testing.T) {
+	want := []models.Mod{
+		{ID: "minecraft", Version: "1.20.1"},
+		{ID: "forge", Version: "47.2.0"},
+		{ID: "serverutils"},
+	}
+
+	mods, err := decodeForgeMods(encodeForgeMods(want))
+	if err != nil {
+		t.Fatal("Decode failed:", err)
+	}
+	if len(mods) != len(want) {
+		t.Fatalf("Expected %d mods, got %+v", len(want), mods)
+	}
+	for i := range want {
+		if mods[i] != want[i] {
+			t.Errorf("Mod %d: expected %+v, got %+v", i, want[i], mods[i])
+		}
+	}
+
+	if _, err := decodeForgeMods("x"); err == nil {
+		t.Error("Expected an error for truncated data")
+	}
+}
+
+func TestProbeMinecraft_IPv6(t *testing.T) {
+	port := startStatusResponder(t, "::1", `{"version": {"name": "1.20.4", "protocol": 765}, "players": {"max": 20, "online": 1}, "description": ""}`)
+
+	status, err := ProbeMinecraft(withTimeout(t, time.Second), &models.Server{Address: "::1", Port: port})
+	if err != nil {
+		t.Fatal("Probe failed:", err)
+	}
+	if status.Players != 1 || status.ResolvedAddress != net.JoinHostPort("::1", strconv.Itoa(port)) {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+}
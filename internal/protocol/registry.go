@@ -0,0 +1,164 @@
+// Package protocol holds the registry of supported game server types. Each
+// type registers the prober that queries it together with its default port,
+// display name and capabilities; server validation, the prober and the
+// /api/server-types endpoint are all driven by the registry.
+package protocol
+
+import (
+	"context"
+	"game-server-monitor/internal/models"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Capabilities advertised by server types, describing what their probes report
+const (
+	CapabilityPlayers    = "players"     // Player and slot counts
+	CapabilityPlayerList = "player_list" // Names of online players
+	CapabilityVersion    = "version"     // Server version
+	CapabilityMOTD       = "motd"        // Message of the day
+	CapabilityGameMode   = "game_mode"   // Advertised game mode
+	CapabilityRules      = "rules"       // Server rules / cvars
+	CapabilityIcon       = "icon"        // Server icon, see /api/servers/:id/icon
+	CapabilityMods       = "mods"        // Installed mods of modded servers
+	CapabilityRCON       = "rcon"        // Remote console, see POST /api/admin/servers/:id/rcon
+	CapabilityTLS        = "tls"         // TLS certificate details and expiry
+)
+
+// Prober queries the status of a server of one type. The probe must give
+// up, including any network I/O in flight, when ctx is done; the deadline of
+// ctx is the probe timeout.
+type Prober interface {
+	Probe(ctx context.Context, server *models.Server) (*models.ServerStatus, error)
+}
+
+// ProberFunc adapts a function to the Prober interface
+type ProberFunc func(ctx context.Context, server *models.Server) (*models.ServerStatus, error)
+
+// Probe calls f(ctx, server)
+func (f ProberFunc) Probe(ctx context.Context, server *models.Server) (*models.ServerStatus, error) {
+	return f(ctx, server)
+}
+
+// ServerType describes a registered game server type
+type ServerType struct {
+	Name         string   `json:"name"`         // Identifier stored in Server.Type
+	DisplayName  string   `json:"display_name"` // Human readable name
+	DefaultPort  int      `json:"default_port"`
+	Transport    string   `json:"transport"` // "tcp" or "udp"
+	Capabilities []string `json:"capabilities"`
+	SRVService   string   `json:"srv_service,omitempty"` // SRV service name; such servers may omit the port
+	Prober       Prober   `json:"-"`
+
+	// Query port used when a server has none configured: the game port plus
+	// QueryPortOffset if set, else DefaultQueryPort if set, else the game port
+	QueryPortOffset  int `json:"query_port_offset,omitempty"`
+	DefaultQueryPort int `json:"default_query_port,omitempty"`
+
+	// RCON port used when a server has none configured, 0 for the game port
+	DefaultRCONPort int `json:"default_rcon_port,omitempty"`
+
+	// ValidateCheck validates the check settings of servers of this type, if set
+	ValidateCheck func(check *models.HealthCheck) error `json:"-"`
+}
+
+// QueryPort returns the port a server of this type is queried on
+func (t *ServerType) QueryPort(server *models.Server) int {
+	switch {
+	case server.QueryPort > 0:
+		return server.QueryPort
+	case t.QueryPortOffset != 0:
+		return server.Port + t.QueryPortOffset
+	case t.DefaultQueryPort != 0:
+		return t.DefaultQueryPort
+	default:
+		return server.Port
+	}
+}
+
+// RCONPort returns the port a server of this type accepts RCON connections on
+func (t *ServerType) RCONPort(server *models.Server) int {
+	switch {
+	case server.RCONPort > 0:
+		return server.RCONPort
+	case t.DefaultRCONPort != 0:
+		return t.DefaultRCONPort
+	default:
+		return server.Port
+	}
+}
+
+// HasCapability reports whether the type advertises a capability
+func (t *ServerType) HasCapability(capability string) bool {
+	for _, c := range t.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	registry = make(map[string]*ServerType)
+	mutex    sync.RWMutex
+)
+
+// Register adds a server type to the registry. It panics if the name is
+// empty, already registered or the type has no prober, as registration
+// happens at init time.
+func Register(serverType ServerType) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if serverType.Name == "" {
+		panic("protocol: server type without name")
+	}
+	if serverType.Prober == nil {
+		panic("protocol: server type " + serverType.Name + " has no prober")
+	}
+	if _, exists := registry[serverType.Name]; exists {
+		panic("protocol: server type " + serverType.Name + " registered twice")
+	}
+	registry[serverType.Name] = &serverType
+}
+
+// Lookup returns the registered server type with the given name
+func Lookup(name string) (*ServerType, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	serverType, exists := registry[name]
+	return serverType, exists
+}
+
+// IsValid reports whether a server type is registered
+func IsValid(name string) bool {
+	_, exists := Lookup(name)
+	return exists
+}
+
+// Types returns all registered server types sorted by name
+func Types() []ServerType {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	types := make([]ServerType, 0, len(registry))
+	for _, serverType := range registry {
+		types = append(types, *serverType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name < types[j].Name
+	})
+	return types
+}
+
+// Names returns the names of all registered server types as a comma-separated list
+func Names() string {
+	types := Types()
+	names := make([]string, len(types))
+	for i, serverType := range types {
+		names[i] = serverType.Name
+	}
+	return strings.Join(names, ", ")
+}
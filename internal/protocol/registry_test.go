@@ -0,0 +1,70 @@
+package protocol
+
+import (
+	"context"
+	"game-server-monitor/internal/models"
+	"testing"
+)
+
+func TestBuiltinTypes(t *testing.T) {
+	tests := []struct {
+		name      string
+		port      int
+		transport string
+	}{
+		{"minecraft", 25565, "tcp"},
+		{"minecraft_bedrock", 19132, "udp"},
+		{"cs2", 27015, "udp"},
+	}
+
+	for _, tt := range tests {
+		serverType, exists := Lookup(tt.name)
+		if !exists {
+			t.Errorf("Expected %s to be registered", tt.name)
+			continue
+		}
+		if serverType.DefaultPort != tt.port || serverType.Transport != tt.transport {
+			t.Errorf("%s: unexpected port %d / transport %s", tt.name, serverType.DefaultPort, serverType.Transport)
+		}
+		if !serverType.HasCapability(CapabilityPlayers) {
+			t.Errorf("%s: expected players capability", tt.name)
+		}
+	}
+
+	if IsValid("quake") {
+		t.Error("Expected unknown type to be invalid")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	probe := ProberFunc(func(ctx context.Context, server *models.Server) (*models.ServerStatus, error) {
+		return &models.ServerStatus{Online: true}, nil
+	})
+
+	Register(ServerType{Name: "test_game", DisplayName: "Test Game", DefaultPort: 1234, Prober: probe})
+	defer func() {
+		mutex.Lock()
+		delete(registry, "test_game")
+		mutex.Unlock()
+	}()
+
+	serverType, exists := Lookup("test_game")
+	if !exists {
+		t.Fatal("Expected registered type to be found")
+	}
+	if status, err := serverType.Prober.Probe(context.Background(), &models.Server{Address: "localhost", Port: 1234}); err != nil || !status.Online {
+		t.Errorf("Unexpected probe result: %+v (%v)", status, err)
+	}
+
+	expectPanic := func(name string, serverType ServerType) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected %s to panic", name)
+			}
+		}()
+		Register(serverType)
+	}
+	expectPanic("duplicate", ServerType{Name: "test_game", Prober: probe})
+	expectPanic("no name", ServerType{Prober: probe})
+	expectPanic("no prober", ServerType{Name: "other_game"})
+}
@@ -0,0 +1,146 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"game-server-monitor/internal/models"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// Resolver looks up the DNS records used to locate servers. *net.Resolver
+// implements it; tests substitute one pointed at a local DNS server.
+type Resolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+}
+
+var (
+	resolver      Resolver = net.DefaultResolver
+	resolverMutex sync.RWMutex
+)
+
+// SetResolver replaces the resolver used by the probers and returns the previous one
+func SetResolver(r Resolver) Resolver {
+	resolverMutex.Lock()
+	defer resolverMutex.Unlock()
+
+	previous := resolver
+	resolver = r
+	return previous
+}
+
+// currentResolver returns the resolver used by the probers
+func currentResolver() Resolver {
+	resolverMutex.RLock()
+	defer resolverMutex.RUnlock()
+	return resolver
+}
+
+// Target is the resolved network location of a server
+type Target struct {
+	Host string // Host name to announce to the server, the SRV target if one was used
+	IP   net.IP
+	Port int
+	SRV  string // host:port from the SRV record, empty if none was used
+}
+
+// Address returns the IP and port of the target in dialable form
+func (t *Target) Address() string {
+	return net.JoinHostPort(t.IP.String(), strconv.Itoa(t.Port))
+}
+
+// ResolveHost resolves a host name, or parses an IP literal, to an IP of
+// the given family ("" for either)
+func ResolveHost(ctx context.Context, host, family string) (net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		if !inFamily(ip, family) {
+			return nil, fmt.Errorf("%s is not an %s address", host, familyName(family))
+		}
+		return ip, nil
+	}
+
+	network := family
+	if network == "" {
+		network = "ip"
+	}
+
+	ips, err := currentResolver().LookupIP(ctx, network, host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no %s addresses found for %s", familyName(family), host)
+	}
+	return ips[0], nil
+}
+
+// ResolveAddress resolves the address of a server, restricted to its
+// family, and returns it joined with the given port
+func ResolveAddress(ctx context.Context, server *models.Server, port int) (string, error) {
+	ip, err := ResolveHost(ctx, server.Address, server.Family)
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(ip.String(), strconv.Itoa(port)), nil
+}
+
+// ResolveSRV locates a server the way game clients do. When the server has
+// no port or the default port of its type, the type's SRV record of its
+// address is looked up first; its target, or the address itself, is then
+// resolved to an IP of the server's family.
+func ResolveSRV(ctx context.Context, server *models.Server, serverType *ServerType) (*Target, error) {
+	target := &Target{Host: server.Address, Port: server.Port}
+	if target.Port == 0 {
+		target.Port = serverType.DefaultPort
+	}
+
+	if net.ParseIP(server.Address) == nil && (server.Port == 0 || server.Port == serverType.DefaultPort) {
+		// A missing SRV record is normal; fall back to the address itself
+		if _, records, err := currentResolver().LookupSRV(ctx, serverType.SRVService, serverType.Transport, server.Address); err == nil && len(records) > 0 {
+			target.Host = trimDot(records[0].Target)
+			target.Port = int(records[0].Port)
+			target.SRV = net.JoinHostPort(target.Host, strconv.Itoa(target.Port))
+		}
+	}
+
+	ip, err := ResolveHost(ctx, target.Host, server.Family)
+	if err != nil {
+		return nil, err
+	}
+	target.IP = ip
+	return target, nil
+}
+
+// inFamily reports whether an IP belongs to a family ("" matches any)
+func inFamily(ip net.IP, family string) bool {
+	switch family {
+	case models.FamilyIPv4:
+		return ip.To4() != nil
+	case models.FamilyIPv6:
+		return ip.To4() == nil
+	default:
+		return true
+	}
+}
+
+// familyName returns the display name of a family
+func familyName(family string) string {
+	switch family {
+	case models.FamilyIPv4:
+		return "IPv4"
+	case models.FamilyIPv6:
+		return "IPv6"
+	default:
+		return "IP"
+	}
+}
+
+// trimDot removes the trailing dot of a fully qualified domain name
+func trimDot(name string) string {
+	if len(name) > 0 && name[len(name)-1] == '.' {
+		return name[:len(name)-1]
+	}
+	return name
+}
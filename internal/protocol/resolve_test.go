@@ -0,0 +1,160 @@
+package protocol
+
+import (
+	"context"
+	"game-server-monitor/internal/models"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startDNSServer answers DNS queries on a local UDP port from the given SRV
+// records and host addresses and returns a resolver that uses it. Unknown
+// names get NXDOMAIN.
+func startDNSServer(t *testing.T, srv map[string]dnsmessage.SRVResource, hosts map[string][]string) *net.Resolver {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Failed to listen:", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+
+			var request dnsmessage.Message
+			if err := request.Unpack(buffer[:n]); err != nil || len(request.Questions) != 1 {
+				continue
+			}
+			question := request.Questions[0]
+			name := question.Name.String()
+
+			response := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: request.ID, Response: true, Authoritative: true},
+				Questions: request.Questions,
+			}
+			header := dnsmessage.ResourceHeader{Name: question.Name, Type: question.Type, Class: dnsmessage.ClassINET, TTL: 60}
+
+			record, hasSRV := srv[name]
+			ips, hasHost := hosts[name]
+			switch {
+			case question.Type == dnsmessage.TypeSRV && hasSRV:
+				response.Answers = append(response.Answers, dnsmessage.Resource{Header: header, Body: &record})
+			case !hasSRV && !hasHost:
+				response.RCode = dnsmessage.RCodeNameError
+			}
+			for _, address := range ips {
+				ip := net.ParseIP(address)
+				if ip4 := ip.To4(); ip4 != nil && question.Type == dnsmessage.TypeA {
+					response.Answers = append(response.Answers, dnsmessage.Resource{Header: header, Body: &dnsmessage.AResource{A: [4]byte(ip4)}})
+				} else if ip4 == nil && question.Type == dnsmessage.TypeAAAA {
+					response.Answers = append(response.Answers, dnsmessage.Resource{Header: header, Body: &dnsmessage.AAAAResource{AAAA: [16]byte(ip)}})
+				}
+			}
+
+			packet, err := response.Pack()
+			if err != nil {
+				t.Errorf("Failed to pack DNS response: %v", err)
+				continue
+			}
+			conn.WriteTo(packet, addr)
+		}
+	}()
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "udp", conn.LocalAddr().String())
+		},
+	}
+}
+
+// useResolver replaces the package resolver for the duration of a test
+func useResolver(t *testing.T, r Resolver) {
+	previous := SetResolver(r)
+	t.Cleanup(func() { SetResolver(previous) })
+}
+
+func TestResolveSRV(t *testing.T) {
+	useResolver(t, startDNSServer(t,
+		map[string]dnsmessage.SRVResource{
+			"_minecraft._tcp.play.example.com.": {Target: dnsmessage.MustNewName("mc1.example.com."), Port: 25570},
+		},
+		map[string][]string{
+			"mc1.example.com.":   {"127.0.0.2"},
+			"plain.example.com.": {"127.0.0.3"},
+			"dual.example.com.":  {"127.0.0.4", "2001:db8::4"},
+		},
+	))
+
+	tests := []struct {
+		name    string
+		address string
+		port    int
+		family  string
+		want    string // Resolved address
+		srv     string
+	}{
+		{"SRV without port", "play.example.com", 0, "", "127.0.0.2:25570", "mc1.example.com:25570"},
+		{"SRV on default port", "play.example.com", 25565, "", "127.0.0.2:25570", "mc1.example.com:25570"},
+		{"explicit port skips SRV", "mc1.example.com", 25566, "", "127.0.0.2:25566", ""},
+		{"no SRV record", "plain.example.com", 0, "", "127.0.0.3:25565", ""},
+		{"IP literal", "127.0.0.9", 0, "", "127.0.0.9:25565", ""},
+		{"IPv6 literal", "::1", 25570, "", "[::1]:25570", ""},
+		{"IPv4 only", "dual.example.com", 0, models.FamilyIPv4, "127.0.0.4:25565", ""},
+		{"IPv6 only", "dual.example.com", 0, models.FamilyIPv6, "[2001:db8::4]:25565", ""},
+	}
+
+	minecraft, _ := Lookup("minecraft")
+	for _, tt := range tests {
+		server := &models.Server{Address: tt.address, Port: tt.port, Family: tt.family}
+		target, err := ResolveSRV(withTimeout(t, time.Second), server, minecraft)
+		if err != nil {
+			t.Errorf("%s: resolve failed: %v", tt.name, err)
+			continue
+		}
+		if target.Address() != tt.want || target.SRV != tt.srv {
+			t.Errorf("%s: expected %s via %q, got %s via %q", tt.name, tt.want, tt.srv, target.Address(), target.SRV)
+		}
+	}
+
+	if _, err := ResolveSRV(withTimeout(t, time.Second), &models.Server{Address: "missing.example.com"}, minecraft); err == nil {
+		t.Error("Expected an error for an unknown host")
+	}
+	if _, err := ResolveSRV(withTimeout(t, time.Second), &models.Server{Address: "plain.example.com", Family: models.FamilyIPv6}, minecraft); err == nil {
+		t.Error("Expected an error for a host without IPv6 addresses")
+	}
+	if _, err := ResolveHost(withTimeout(t, time.Second), "127.0.0.1", models.FamilyIPv6); err == nil {
+		t.Error("Expected an error for an IPv4 literal restricted to IPv6")
+	}
+}
+
+func TestProbeMinecraft_SRV(t *testing.T) {
+	port := startStatusResponder(t, "127.0.0.1", `{"version": {"name": "1.20.4", "protocol": 765}, "players": {"max": 20, "online": 3}, "description": "A server"}`)
+
+	useResolver(t, startDNSServer(t,
+		map[string]dnsmessage.SRVResource{
+			"_minecraft._tcp.play.example.com.": {Target: dnsmessage.MustNewName("mc1.example.com."), Port: uint16(port)},
+		},
+		map[string][]string{"mc1.example.com.": {"127.0.0.1"}},
+	))
+
+	status, err := ProbeMinecraft(withTimeout(t, time.Second), &models.Server{Address: "play.example.com"})
+	if err != nil {
+		t.Fatal("Probe failed:", err)
+	}
+	if status.Players != 3 || status.MOTD != "A server" {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+	if status.SRVTarget != net.JoinHostPort("mc1.example.com", strconv.Itoa(port)) || status.ResolvedAddress != net.JoinHostPort("127.0.0.1", strconv.Itoa(port)) {
+		t.Errorf("Unexpected resolution %q / %q", status.SRVTarget, status.ResolvedAddress)
+	}
+}
@@ -0,0 +1,203 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Java Edition Server List Ping packet IDs
+const (
+	slpHandshake = 0x00
+	slpStatus    = 0x00
+	slpPing      = 0x01
+)
+
+// slpMaxPacket bounds the length of a status response; favicons make up
+// most of it and are far smaller than this
+const slpMaxPacket = 1 << 21
+
+// JavaStatus is the status JSON a Java Edition server answers a Server List Ping with
+type JavaStatus struct {
+	Version struct {
+		Name     string `json:"name"`
+		Protocol int    `json:"protocol"`
+	} `json:"version"`
+	Players struct {
+		Max    int `json:"max"`
+		Online int `json:"online"`
+		Sample []struct {
+			Name string `json:"name"`
+			ID   string `json:"id"`
+		} `json:"sample"`
+	} `json:"players"`
+	Description        interface{} `json:"description"` // Chat component or legacy string
+	Favicon            string      `json:"favicon"`     // data:image/png;base64,...
+	EnforcesSecureChat *bool       `json:"enforcesSecureChat"`
+
+	// Forge 1.7 - 1.12
+	ModInfo *struct {
+		Type    string `json:"type"`
+		ModList []struct {
+			ModID   string `json:"modid"`
+			Version string `json:"version"`
+		} `json:"modList"`
+	} `json:"modinfo"`
+
+	// Forge 1.13+ and NeoForge; since Forge 1.18 the mods are packed into D
+	ForgeData *struct {
+		FMLNetworkVersion int `json:"fmlNetworkVersion"`
+		Mods              []struct {
+			ModID     string `json:"modId"`
+			ModMarker string `json:"modmarker"`
+		} `json:"mods"`
+		D         string `json:"d"`
+		Truncated bool   `json:"truncated"`
+	} `json:"forgeData"`
+}
+
+// QueryJava performs a Server List Ping against a resolved Java Edition
+// server and returns its status together with the measured round trip time
+func QueryJava(ctx context.Context, target *Target) (*JavaStatus, time.Duration, error) {
+	conn, err := dial(ctx, "tcp", target.Address())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+	defer bindConn(ctx, conn)()
+
+	// Handshake with protocol version -1 ("unknown") and next state status,
+	// immediately followed by the status request
+	var handshake bytes.Buffer
+	writeVarInt(&handshake, slpHandshake)
+	writeVarInt(&handshake, -1)
+	writeString(&handshake, target.Host)
+	binary.Write(&handshake, binary.BigEndian, uint16(target.Port))
+	writeVarInt(&handshake, 1)
+
+	var request bytes.Buffer
+	writePacket(&request, handshake.Bytes())
+	writePacket(&request, []byte{slpStatus})
+	if _, err := conn.Write(request.Bytes()); err != nil {
+		return nil, 0, err
+	}
+
+	reader := bufio.NewReader(conn)
+	packet, err := readPacket(reader, slpStatus)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read status response: %w", err)
+	}
+	data, err := readString(bytes.NewReader(packet))
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid status response: %w", err)
+	}
+
+	var status JavaStatus
+	if err := json.Unmarshal([]byte(data), &status); err != nil {
+		return nil, 0, fmt.Errorf("invalid status JSON: %w", err)
+	}
+
+	// Measure the round trip with a ping; servers that close the connection
+	// after the status response still count as online
+	var ping bytes.Buffer
+	ping.WriteByte(slpPing)
+	binary.Write(&ping, binary.BigEndian, time.Now().UnixMilli())
+	request.Reset()
+	writePacket(&request, ping.Bytes())
+
+	start := time.Now()
+	if _, err := conn.Write(request.Bytes()); err != nil {
+		return &status, 0, nil
+	}
+	if _, err := readPacket(reader, slpPing); err != nil {
+		return &status, 0, nil
+	}
+	return &status, time.Since(start), nil
+}
+
+// writePacket writes a length-prefixed packet
+func writePacket(w *bytes.Buffer, payload []byte) {
+	writeVarInt(w, int32(len(payload)))
+	w.Write(payload)
+}
+
+// readPacket reads a length-prefixed packet with the expected ID and returns its payload
+func readPacket(r *bufio.Reader, id int32) ([]byte, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if length <= 0 || length > slpMaxPacket {
+		return nil, fmt.Errorf("invalid packet length %d", length)
+	}
+
+	packet := make([]byte, length)
+	if _, err := io.ReadFull(r, packet); err != nil {
+		return nil, err
+	}
+
+	payload := bytes.NewReader(packet)
+	packetID, err := readVarInt(payload)
+	if err != nil {
+		return nil, err
+	}
+	if packetID != id {
+		return nil, fmt.Errorf("unexpected packet 0x%02x", packetID)
+	}
+	return packet[len(packet)-payload.Len():], nil
+}
+
+// writeVarInt writes a protocol VarInt
+func writeVarInt(w *bytes.Buffer, value int32) {
+	v := uint32(value)
+	for v >= 0x80 {
+		w.WriteByte(byte(v) | 0x80)
+		v >>= 7
+	}
+	w.WriteByte(byte(v))
+}
+
+// readVarInt reads a protocol VarInt of at most five bytes
+func readVarInt(r io.ByteReader) (int32, error) {
+	var value uint32
+	for i := 0; i < 5; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint32(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(value), nil
+		}
+	}
+	return 0, errors.New("VarInt too long")
+}
+
+// writeString writes a VarInt length-prefixed UTF-8 string
+func writeString(w *bytes.Buffer, s string) {
+	writeVarInt(w, int32(len(s)))
+	w.WriteString(s)
+}
+
+// readString reads a VarInt length-prefixed UTF-8 string
+func readString(r *bytes.Reader) (string, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return "", err
+	}
+	if length < 0 || int(length) > r.Len() {
+		return "", fmt.Errorf("invalid string length %d", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,189 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"game-server-monitor/internal/models"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rumblefrog/go-a2s"
+)
+
+// sourceCapabilities are the capabilities of all A2S game types
+var sourceCapabilities = []string{CapabilityPlayers, CapabilityPlayerList, CapabilityVersion, CapabilityRules}
+
+// sourceQuirk adjusts a status for a game that bends the A2S conventions
+type sourceQuirk func(status *models.ServerStatus)
+
+func init() {
+	rcon := []string{CapabilityRCON}
+	registerSourceGame(ServerType{Name: "cs2", DisplayName: "Counter-Strike 2", DefaultPort: 27015, Capabilities: rcon}, nil)
+	registerSourceGame(ServerType{Name: "tf2", DisplayName: "Team Fortress 2", DefaultPort: 27015, Capabilities: rcon}, nil)
+	registerSourceGame(ServerType{Name: "gmod", DisplayName: "Garry's Mod", DefaultPort: 27015, Capabilities: rcon}, nil)
+	registerSourceGame(ServerType{Name: "rust", DisplayName: "Rust", DefaultPort: 28015, QueryPortOffset: 2, Capabilities: rcon, DefaultRCONPort: 28016}, rustQuirk)
+	registerSourceGame(ServerType{Name: "ark", DisplayName: "ARK: Survival Evolved", DefaultPort: 7777, DefaultQueryPort: 27015, Capabilities: rcon, DefaultRCONPort: 27020}, arkQuirk)
+	registerSourceGame(ServerType{Name: "valheim", DisplayName: "Valheim", DefaultPort: 2456, QueryPortOffset: 1}, nil)
+	registerSourceGame(ServerType{Name: "palworld", DisplayName: "Palworld", DefaultPort: 8211, DefaultQueryPort: 27015, Capabilities: rcon, DefaultRCONPort: 25575}, nil)
+}
+
+// registerSourceGame registers a game type queried over A2S. Capabilities
+// given in serverType are added to those of all A2S games.
+func registerSourceGame(serverType ServerType, quirk sourceQuirk) {
+	serverType.Transport = "udp"
+	serverType.Capabilities = append(append([]string(nil), sourceCapabilities...), serverType.Capabilities...)
+	queryPort := serverType.QueryPort
+	serverType.Prober = ProberFunc(func(ctx context.Context, server *models.Server) (*models.ServerStatus, error) {
+		status, err := querySource(ctx, server, queryPort(server))
+		if err == nil && quirk != nil {
+			quirk(status)
+		}
+		return status, err
+	})
+	Register(serverType)
+}
+
+// querySource queries a server using the Source Query protocol. A2S_INFO
+// decides whether the server is online; the A2S_PLAYER roster and, for
+// servers with EnableQuery set, A2S_RULES are collected when available.
+func querySource(ctx context.Context, server *models.Server, port int) (*models.ServerStatus, error) {
+	startTime := time.Now()
+
+	serverAddr, err := ResolveAddress(ctx, server, port)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create A2S client; closing it when ctx is done interrupts a pending query
+	client, err := a2s.NewClient(serverAddr, a2s.TimeoutOption(timeoutOf(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create A2S client for %s: %w", serverAddr, err)
+	}
+	defer client.Close()
+	defer context.AfterFunc(ctx, func() { client.Close() })()
+
+	// Query server info
+	info, err := client.QueryInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Source server info %s: %w", serverAddr, err)
+	}
+
+	// Calculate ping
+	ping := time.Since(startTime).Milliseconds()
+
+	// Parse the response
+	serverStatus := &models.ServerStatus{
+		Online:      true,
+		Players:     int(info.Players),
+		MaxPlayers:  int(info.MaxPlayers),
+		Version:     info.Version,
+		Ping:        ping,
+		Source:      sourceInfo(info),
+		LastUpdated: time.Now(),
+
+		ResolvedAddress: serverAddr,
+	}
+
+	// Servers may refuse the extra queries; that does not make them offline
+	if players, err := client.QueryPlayer(); err != nil {
+		log.Printf("A2S_PLAYER query of %s failed: %v", serverAddr, err)
+	} else {
+		serverStatus.PlayerList = sourcePlayers(players)
+	}
+
+	if server.EnableQuery {
+		if rules, err := client.QueryRules(); err != nil {
+			log.Printf("A2S_RULES query of %s failed: %v", serverAddr, err)
+		} else {
+			serverStatus.Rules = rules.Rules
+		}
+	}
+
+	return serverStatus, nil
+}
+
+// sourceInfo extracts the details of an A2S_INFO response
+func sourceInfo(info *a2s.ServerInfo) *models.SourceInfo {
+	details := &models.SourceInfo{
+		Name:        info.Name,
+		Map:         info.Map,
+		Folder:      info.Folder,
+		Game:        info.Game,
+		AppID:       uint64(info.ID),
+		Bots:        int(info.Bots),
+		ServerType:  info.ServerType.String(),
+		Environment: info.ServerOS.String(),
+		Private:     info.Visibility,
+		VAC:         info.VAC,
+	}
+
+	if extended := info.ExtendedServerInfo; extended != nil {
+		details.Keywords = extended.Keywords
+		// The 64-bit game ID holds the untruncated app ID in its low 24 bits
+		if extended.GameID != 0 {
+			details.AppID = extended.GameID & 0xffffff
+		}
+	}
+
+	return details
+}
+
+// sourcePlayers converts an A2S_PLAYER response, skipping players that are
+// still connecting and have no name yet
+func sourcePlayers(info *a2s.PlayerInfo) []models.Player {
+	players := make([]models.Player, 0, len(info.Players))
+	for _, player := range info.Players {
+		if player.Name == "" {
+			continue
+		}
+		players = append(players, models.Player{
+			Name:     player.Name,
+			Score:    int(player.Score),
+			Duration: float64(player.Duration),
+		})
+	}
+	return players
+}
+
+// rustQuirk reads the player counts from Rust's keywords (cp<players>,
+// mp<max players>, qp<queue>), as A2S_INFO caps them at 255
+func rustQuirk(status *models.ServerStatus) {
+	if status.Source == nil {
+		return
+	}
+	for _, keyword := range strings.Split(status.Source.Keywords, ",") {
+		if len(keyword) < 3 {
+			continue
+		}
+		value, err := strconv.Atoi(keyword[2:])
+		if err != nil {
+			continue
+		}
+		switch keyword[:2] {
+		case "cp":
+			status.Players = value
+		case "mp":
+			status.MaxPlayers = value
+		case "qp":
+			status.Source.Queue = value
+		}
+	}
+}
+
+// arkSessionVersion matches the version ARK appends to session names, e.g. "My Server - (v358.24)"
+var arkSessionVersion = regexp.MustCompile(`^(.*) - \(v([0-9.]+)\)$`)
+
+// arkQuirk moves the game version from the session name to the status, as
+// ARK's A2S version field is always 1.0.0.0
+func arkQuirk(status *models.ServerStatus) {
+	if status.Source == nil {
+		return
+	}
+	if match := arkSessionVersion.FindStringSubmatch(status.Source.Name); match != nil {
+		status.Source.Name = match[1]
+		status.Version = match[2]
+	}
+}
@@ -0,0 +1,213 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"game-server-monitor/internal/models"
+	"math"
+	"net"
+	"testing"
+	"time"
+)
+
+// a2sPacket builds A2S response payloads
+type a2sPacket struct {
+	bytes.Buffer
+}
+
+func (p *a2sPacket) str(s string) *a2sPacket {
+	p.WriteString(s)
+	p.WriteByte(0)
+	return p
+}
+
+func (p *a2sPacket) u8(v uint8) *a2sPacket {
+	p.WriteByte(v)
+	return p
+}
+
+func (p *a2sPacket) le(v interface{}) *a2sPacket {
+	binary.Write(p, binary.LittleEndian, v)
+	return p
+}
+
+// startA2SResponder answers A2S_INFO immediately and A2S_PLAYER / A2S_RULES
+// after a challenge on a local UDP port. Responses are given without the
+// 0xFFFFFFFF header; a nil response leaves that query unanswered.
+func startA2SResponder(t *testing.T, info, players, rules []byte) int {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Failed to listen:", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	challenge := []byte{0x4b, 0xa1, 0xd5, 0x22}
+	header := []byte{0xff, 0xff, 0xff, 0xff}
+
+	go func() {
+		buffer := make([]byte, 1400)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			if n < 5 || !bytes.Equal(buffer[:4], header) {
+				t.Errorf("Unexpected A2S packet: %x", buffer[:n])
+				continue
+			}
+
+			var response []byte
+			switch buffer[4] {
+			case 0x54: // A2S_INFO
+				response = info
+			case 0x55, 0x56: // A2S_PLAYER, A2S_RULES
+				if n != 9 || !bytes.Equal(buffer[5:9], challenge) {
+					response = append([]byte{0x41}, challenge...)
+				} else if buffer[4] == 0x55 {
+					response = players
+				} else {
+					response = rules
+				}
+			}
+
+			if response != nil {
+				conn.WriteTo(append(append([]byte{}, header...), response...), addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// cs2Info returns an A2S_INFO response of a CS2 server with extra data fields
+func cs2Info() []byte {
+	info := &a2sPacket{}
+	info.u8(0x49).u8(17).str("Community Server").str("de_dust2").str("csgo").str("Counter-Strike 2")
+	info.le(uint16(730)).u8(12).u8(32).u8(2).u8('d').u8('l').u8(1).u8(1).str("1.40.1.1")
+	info.u8(0x20 | 0x01).str("secure,competitive").le(uint64(730))
+	return info.Bytes()
+}
+
+func TestProbeSource(t *testing.T) {
+	players := &a2sPacket{}
+	players.u8(0x44).u8(3)
+	players.u8(0).str("alice").le(uint32(21)).le(math.Float32bits(125.5))
+	players.u8(1).str("").le(uint32(0)).le(math.Float32bits(1)) // Still connecting
+	players.u8(2).str("bob").le(uint32(7)).le(math.Float32bits(60))
+
+	rules := &a2sPacket{}
+	rules.u8(0x45).le(uint16(2)).str("mp_friendlyfire").str("0").str("sv_cheats").str("0")
+
+	port := startA2SResponder(t, cs2Info(), players.Bytes(), rules.Bytes())
+	server := &models.Server{Address: "127.0.0.1", Port: port, EnableQuery: true}
+
+	cs2, _ := Lookup("cs2")
+	status, err := cs2.Prober.Probe(withTimeout(t, time.Second), server)
+	if err != nil {
+		t.Fatal("Probe failed:", err)
+	}
+
+	if !status.Online || status.Players != 12 || status.MaxPlayers != 32 || status.Version != "1.40.1.1" {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	expected := models.SourceInfo{
+		Name:        "Community Server",
+		Map:         "de_dust2",
+		Folder:      "csgo",
+		Game:        "Counter-Strike 2",
+		AppID:       730,
+		Bots:        2,
+		ServerType:  "Dedicated",
+		Environment: "Linux",
+		Private:     true,
+		VAC:         true,
+		Keywords:    "secure,competitive",
+	}
+	if status.Source == nil || *status.Source != expected {
+		t.Errorf("Expected %+v, got %+v", expected, status.Source)
+	}
+
+	if len(status.PlayerList) != 2 || status.PlayerList[0] != (models.Player{Name: "alice", Score: 21, Duration: 125.5}) ||
+		status.PlayerList[1].Name != "bob" {
+		t.Errorf("Unexpected players: %+v", status.PlayerList)
+	}
+
+	if len(status.Rules) != 2 || status.Rules["mp_friendlyfire"] != "0" {
+		t.Errorf("Unexpected rules: %v", status.Rules)
+	}
+}
+
+func TestProbeSource_ExtrasUnanswered(t *testing.T) {
+	// Only A2S_INFO is answered; the server is still online
+	port := startA2SResponder(t, cs2Info(), nil, nil)
+	server := &models.Server{Address: "127.0.0.1", Port: port}
+
+	cs2, _ := Lookup("cs2")
+	status, err := cs2.Prober.Probe(withTimeout(t, 200*time.Millisecond), server)
+	if err != nil {
+		t.Fatal("Probe failed:", err)
+	}
+	if !status.Online || status.Source == nil || status.PlayerList != nil || status.Rules != nil {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+}
+
+func TestSourceGames_QueryPort(t *testing.T) {
+	tests := []struct {
+		serverType string
+		server     models.Server
+		want       int
+	}{
+		{"cs2", models.Server{Port: 27016}, 27016},
+		{"valheim", models.Server{Port: 2456}, 2457},
+		{"rust", models.Server{Port: 28015}, 28017},
+		{"ark", models.Server{Port: 7777}, 27015},
+		{"ark", models.Server{Port: 7777, QueryPort: 27016}, 27016},
+		{"palworld", models.Server{Port: 8211}, 27015},
+	}
+
+	for _, tt := range tests {
+		serverType, exists := Lookup(tt.serverType)
+		if !exists {
+			t.Errorf("Expected %s to be registered", tt.serverType)
+			continue
+		}
+		if got := serverType.QueryPort(&tt.server); got != tt.want {
+			t.Errorf("%s with %+v: expected query port %d, got %d", tt.serverType, tt.server, tt.want, got)
+		}
+	}
+}
+
+func TestSourceGames_RustQuirk(t *testing.T) {
+	info := &a2sPacket{}
+	info.u8(0x49).u8(17).str("Rust Server").str("Procedural Map").str("rust").str("Rust")
+	info.le(uint16(252490 & 0xffff)).u8(255).u8(255).u8(0).u8('d').u8('l').u8(0).u8(1).str("2407")
+	info.u8(0x20).str("mp400,cp312,qp17,ptrak,born1700000000")
+
+	port := startA2SResponder(t, info.Bytes(), nil, nil)
+	server := &models.Server{Type: "rust", Address: "127.0.0.1", Port: 1, QueryPort: port}
+
+	serverType, _ := Lookup("rust")
+	status, err := serverType.Prober.Probe(withTimeout(t, 200*time.Millisecond), server)
+	if err != nil {
+		t.Fatal("Probe failed:", err)
+	}
+	if status.Players != 312 || status.MaxPlayers != 400 || status.Source.Queue != 17 {
+		t.Errorf("Expected 312/400 players with 17 queued, got %d/%d with %d", status.Players, status.MaxPlayers, status.Source.Queue)
+	}
+}
+
+func TestArkQuirk(t *testing.T) {
+	status := &models.ServerStatus{Version: "1.0.0.0", Source: &models.SourceInfo{Name: "Island PvE - (v358.24)"}}
+	arkQuirk(status)
+	if status.Source.Name != "Island PvE" || status.Version != "358.24" {
+		t.Errorf("Unexpected name %q and version %q", status.Source.Name, status.Version)
+	}
+
+	status = &models.ServerStatus{Version: "1.0.0.0", Source: &models.SourceInfo{Name: "Plain name"}}
+	arkQuirk(status)
+	if status.Source.Name != "Plain name" || status.Version != "1.0.0.0" {
+		t.Errorf("Expected status without session version to be unchanged, got %+v", status)
+	}
+}
@@ -0,0 +1,256 @@
+// Package rcon implements the Source RCON protocol, which Minecraft servers
+// speak as well, for running admin commands on game servers.
+package rcon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// Packet types
+const (
+	typeResponseValue = 0
+	typeExecCommand   = 2
+	typeAuthResponse  = 2
+	typeAuth          = 3
+)
+
+// maxPacketSize bounds the size of a received packet; servers split longer
+// output over several packets
+const maxPacketSize = 1 << 16
+
+// commandSeparators are characters game consoles treat as the end of a command
+const commandSeparators = ";\n\r\x00"
+
+// ErrAuthFailed is returned by Dial when the server rejects the password
+var ErrAuthFailed = errors.New("rcon: authentication failed")
+
+// Client is an authenticated RCON connection
+type Client struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+	nextID  int32
+	ctx     context.Context
+	stop    func() bool // Stops interrupting conn when ctx is done
+}
+
+// packet is a single RCON packet
+type packet struct {
+	ID   int32
+	Type int32
+	Body string
+}
+
+// Dial connects to an RCON server and authenticates with the password.
+// Every later operation must complete within timeout.
+func Dial(address, password string, timeout time.Duration) (*Client, error) {
+	return DialContext(context.Background(), address, password, timeout)
+}
+
+// DialContext is like Dial, but gives up connecting, authenticating and
+// running commands as soon as ctx is done
+func DialContext(ctx context.Context, address, password string, timeout time.Duration) (*Client, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(dialCtx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &Client{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		timeout: timeout,
+		nextID:  1,
+		ctx:     ctx,
+		stop: context.AfterFunc(ctx, func() {
+			conn.SetDeadline(time.Unix(1, 0))
+		}),
+	}
+	if err := client.authenticate(password); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	c.stop()
+	return c.conn.Close()
+}
+
+// setDeadline bounds the next operation by the timeout and the deadline of
+// the client's context. It fails if the context is done, as the deadline
+// would otherwise override the interruption.
+func (c *Client) setDeadline() error {
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := c.ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	return c.ctx.Err()
+}
+
+// contextErr returns the error of the client's context if it interrupted
+// an operation, else err
+func (c *Client) contextErr(err error) error {
+	if ctxErr := c.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// authenticate sends the password and waits for the auth response. Source
+// servers send an empty response value before it.
+func (c *Client) authenticate(password string) error {
+	if err := c.setDeadline(); err != nil {
+		return err
+	}
+
+	id := c.id()
+	if err := c.write(packet{ID: id, Type: typeAuth, Body: password}); err != nil {
+		return err
+	}
+
+	for {
+		response, err := c.read()
+		if err != nil {
+			return err
+		}
+		if response.Type != typeAuthResponse {
+			continue
+		}
+		if response.ID == -1 || response.ID != id {
+			return ErrAuthFailed
+		}
+		return nil
+	}
+}
+
+// Execute runs a command and returns its output. Output split over several
+// packets is reassembled: the command is followed by an empty packet that
+// servers answer only after the command's output.
+func (c *Client) Execute(command string) (string, error) {
+	if err := c.setDeadline(); err != nil {
+		return "", err
+	}
+
+	id := c.id()
+	terminator := c.id()
+	if err := c.write(packet{ID: id, Type: typeExecCommand, Body: command}); err != nil {
+		return "", err
+	}
+	if err := c.write(packet{ID: terminator, Type: typeResponseValue}); err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+	for {
+		response, err := c.read()
+		if err != nil {
+			return "", err
+		}
+		switch response.ID {
+		case id:
+			output.WriteString(response.Body)
+		case terminator:
+			return output.String(), nil
+		case -1:
+			return "", ErrAuthFailed
+		}
+	}
+}
+
+// id returns the next request ID
+func (c *Client) id() int32 {
+	id := c.nextID
+	c.nextID++
+	return id
+}
+
+// write sends a packet: size, ID, type, null-terminated body and an empty string
+func (c *Client) write(p packet) error {
+	var buffer bytes.Buffer
+	binary.Write(&buffer, binary.LittleEndian, int32(4+4+len(p.Body)+2))
+	binary.Write(&buffer, binary.LittleEndian, p.ID)
+	binary.Write(&buffer, binary.LittleEndian, p.Type)
+	buffer.WriteString(p.Body)
+	buffer.Write([]byte{0, 0})
+
+	if _, err := c.conn.Write(buffer.Bytes()); err != nil {
+		return c.contextErr(err)
+	}
+	return nil
+}
+
+// read receives a packet
+func (c *Client) read() (*packet, error) {
+	p, err := readPacket(c.reader)
+	if err != nil {
+		return nil, c.contextErr(err)
+	}
+	return p, nil
+}
+
+// readPacket reads a packet from r
+func readPacket(r io.Reader) (*packet, error) {
+	var size int32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return nil, err
+	}
+	if size < 10 || size > maxPacketSize {
+		return nil, fmt.Errorf("rcon: invalid packet size %d", size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	return &packet{
+		ID:   int32(binary.LittleEndian.Uint32(data[0:4])),
+		Type: int32(binary.LittleEndian.Uint32(data[4:8])),
+		Body: string(bytes.TrimRight(data[8:], "\x00")),
+	}, nil
+}
+
+// Allowed reports whether a command is permitted by an allowlist. An entry
+// allows the command itself and the command with arguments, e.g. "say"
+// allows "say hello"; entries are matched case-insensitively. An empty
+// allowlist permits nothing. Commands containing separators (";", line
+// breaks or NUL) are never allowed, since consoles would run each part.
+func Allowed(allowlist []string, command string) bool {
+	if strings.ContainsAny(command, commandSeparators) {
+		return false
+	}
+
+	command = strings.ToLower(strings.TrimSpace(command))
+	if command == "" {
+		return false
+	}
+
+	for _, entry := range allowlist {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if command == entry || strings.HasPrefix(command, entry+" ") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package rcon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startFakeServer runs an RCON server on a local port that accepts the given
+// password and answers commands from the commands map, splitting output into
+// packets of at most 16 bytes. It returns the server address.
+func startFakeServer(t *testing.T, password string, commands map[string]string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Failed to listen:", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFake(conn, password, commands)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// serveFake handles one fake RCON connection
+func serveFake(conn net.Conn, password string, commands map[string]string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authed := false
+
+	send := func(id, kind int32, body string) {
+		var buffer bytes.Buffer
+		binary.Write(&buffer, binary.LittleEndian, int32(10+len(body)))
+		binary.Write(&buffer, binary.LittleEndian, id)
+		binary.Write(&buffer, binary.LittleEndian, kind)
+		buffer.WriteString(body)
+		buffer.Write([]byte{0, 0})
+		conn.Write(buffer.Bytes())
+	}
+
+	for {
+		request, err := readPacket(reader)
+		if err != nil {
+			return
+		}
+
+		switch {
+		case request.Type == typeAuth:
+			// Like srcds, send an empty response value before the auth response
+			send(request.ID, typeResponseValue, "")
+			if request.Body != password {
+				send(-1, typeAuthResponse, "")
+				continue
+			}
+			authed = true
+			send(request.ID, typeAuthResponse, "")
+		case !authed:
+			send(-1, typeAuthResponse, "")
+		case request.Type == typeExecCommand:
+			output, ok := commands[request.Body]
+			if !ok {
+				output = "Unknown command: " + request.Body
+			}
+			for len(output) > 16 {
+				send(request.ID, typeResponseValue, output[:16])
+				output = output[16:]
+			}
+			send(request.ID, typeResponseValue, output)
+		default:
+			send(request.ID, typeResponseValue, "")
+		}
+	}
+}
+
+func TestExecute(t *testing.T) {
+	players := "There are 2 of a max of 20 players online: Alice, Bob"
+	address := startFakeServer(t, "secret", map[string]string{"list": players})
+
+	client, err := Dial(address, "secret", time.Second)
+	if err != nil {
+		t.Fatal("Dial failed:", err)
+	}
+	defer client.Close()
+
+	output, err := client.Execute("list")
+	if err != nil {
+		t.Fatal("Execute failed:", err)
+	}
+	if output != players {
+		t.Errorf("Expected %q, got %q", players, output)
+	}
+
+	// The connection stays usable for further commands
+	output, err = client.Execute("status")
+	if err != nil || !strings.HasPrefix(output, "Unknown command") {
+		t.Errorf("Unexpected second response %q (%v)", output, err)
+	}
+}
+
+func TestDial_WrongPassword(t *testing.T) {
+	address := startFakeServer(t, "secret", nil)
+
+	_, err := Dial(address, "wrong", time.Second)
+	if !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("Expected ErrAuthFailed, got %v", err)
+	}
+}
+
+func TestDialContext_Cancel(t *testing.T) {
+	// A server that accepts connections but never answers
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Failed to listen:", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(io.Discard, conn) // Until the client closes
+				conn.Close()
+			}()
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err = DialContext(ctx, listener.Addr().String(), "secret", 5*time.Second)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Dial took %v after the context was cancelled", elapsed)
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	allowlist := []string{"list", "say", "sv_cheats 0"}
+
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{"list", true},
+		{"LIST", true},
+		{"say hello world", true},
+		{"sv_cheats 0", true},
+		{"sv_cheats 1", false},
+		{"listen", false},
+		{"stop", false},
+		{"", false},
+		{"say hi; quit", false},
+		{"say hi\nstop", false},
+		{"say hi\r\nstop", false},
+		{"list\x00stop", false},
+		{"list;", false},
+	}
+
+	for _, tt := range tests {
+		if got := Allowed(allowlist, tt.command); got != tt.want {
+			t.Errorf("Allowed(%q): expected %t, got %t", tt.command, tt.want, got)
+		}
+	}
+
+	if Allowed(nil, "list") {
+		t.Error("Expected an empty allowlist to permit nothing")
+	}
+}
@@ -0,0 +1,104 @@
+// Package secrets encrypts credentials stored in the database, such as RCON
+// passwords, with AES-256-GCM. The key is derived from the ENCRYPTION_KEY
+// environment variable; changing it makes stored credentials unreadable.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// prefix marks values encrypted by this package and their format version
+const prefix = "enc:v1:"
+
+// defaultKey is used when ENCRYPTION_KEY is not set, for development only
+const defaultKey = "gsm-development-encryption-key"
+
+var (
+	aead     cipher.AEAD
+	loadOnce sync.Once
+	loadErr  error
+)
+
+// load derives the cipher from ENCRYPTION_KEY on first use
+func load() (cipher.AEAD, error) {
+	loadOnce.Do(func() {
+		key := os.Getenv("ENCRYPTION_KEY")
+		if key == "" {
+			log.Println("Warning: ENCRYPTION_KEY is not set, using the development key for stored credentials")
+			key = defaultKey
+		}
+
+		sum := sha256.Sum256([]byte(key))
+		block, err := aes.NewCipher(sum[:])
+		if err != nil {
+			loadErr = err
+			return
+		}
+		aead, loadErr = cipher.NewGCM(block)
+	})
+	return aead, loadErr
+}
+
+// Encrypt encrypts a value for storage. The empty string stays empty.
+func Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	gcm, err := load()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// IsEncrypted reports whether a value was produced by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Decrypt decrypts a value produced by Encrypt. The empty string stays empty.
+func Decrypt(ciphertext string) (string, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+
+	encoded, ok := strings.CutPrefix(ciphertext, prefix)
+	if !ok {
+		return "", errors.New("value is not encrypted")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := load()
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("encrypted value too short")
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt value, was ENCRYPTION_KEY changed?")
+	}
+	return string(plaintext), nil
+}
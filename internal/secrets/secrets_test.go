@@ -0,0 +1,41 @@
+package secrets
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	ciphertext, err := Encrypt("hunter2")
+	if err != nil {
+		t.Fatal("Encrypt failed:", err)
+	}
+	if strings.Contains(ciphertext, "hunter2") || !strings.HasPrefix(ciphertext, prefix) {
+		t.Errorf("Unexpected ciphertext %q", ciphertext)
+	}
+
+	again, _ := Encrypt("hunter2")
+	if again == ciphertext {
+		t.Error("Expected a fresh nonce for every encryption")
+	}
+
+	plaintext, err := Decrypt(ciphertext)
+	if err != nil || plaintext != "hunter2" {
+		t.Errorf("Expected hunter2, got %q (%v)", plaintext, err)
+	}
+
+	if empty, err := Encrypt(""); err != nil || empty != "" {
+		t.Errorf("Expected the empty string to stay empty, got %q (%v)", empty, err)
+	}
+}
+
+func TestDecrypt_Invalid(t *testing.T) {
+	ciphertext, _ := Encrypt("hunter2")
+	tampered := ciphertext[:len(ciphertext)-2] + "AA"
+
+	for _, value := range []string{"hunter2", prefix + "!!", prefix + "AAAA", tampered} {
+		if _, err := Decrypt(value); err == nil {
+			t.Errorf("Expected an error decrypting %q", value)
+		}
+	}
+}
@@ -9,10 +9,13 @@ import (
 	"strings"
 	"time"
 
+	"game-server-monitor/internal/alerts"
 	"game-server-monitor/internal/auth"
 	"game-server-monitor/internal/database"
 	"game-server-monitor/internal/handlers"
+	"game-server-monitor/internal/history"
 	"game-server-monitor/internal/middleware"
+	"game-server-monitor/internal/notify"
 	"game-server-monitor/internal/prober"
 
 	"github.com/gin-gonic/gin"
@@ -45,16 +48,39 @@ func main() {
 
 	// Initialize prober service
 	proberService := prober.NewProberService(dbService)
+
+	// Evaluate alert rules after every probe cycle
+	alertEngine := alerts.NewEngine(dbService, proberService.EventBus())
+	proberService.AddProbeHook(alertEngine.EvaluateServer)
+	proberService.AddCycleHook(alertEngine.Evaluate)
+
 	if err := proberService.Start(); err != nil {
 		log.Fatal("Failed to start prober service:", err)
 	}
 	defer proberService.Stop()
 
+	// Initialize history rollup job (downsampling and retention)
+	historyRollup := history.NewRollup(dbService, nil)
+	if err := historyRollup.Start(); err != nil {
+		log.Fatal("Failed to start history rollup:", err)
+	}
+	defer historyRollup.Stop()
+
+	// Initialize notification dispatcher (webhooks for server state changes)
+	dispatcher := notify.NewDispatcher(dbService, nil)
+	if err := dispatcher.Start(proberService.EventBus()); err != nil {
+		log.Fatal("Failed to start notification dispatcher:", err)
+	}
+	defer dispatcher.Stop()
+
 	// Initialize Gin router
 	r := gin.Default()
 
+	// Uptime summaries are cached per service, so all handlers share one
+	historyService := history.NewService(dbService)
+
 	// Setup routes
-	setupRoutes(r, proberService)
+	setupRoutes(r, proberService, historyService, dispatcher)
 
 	// Get port from environment or use default
 	port := os.Getenv("PORT")
@@ -68,11 +94,17 @@ func main() {
 	log.Fatal(http.ListenAndServe(addr, r))
 }
 
-func setupRoutes(r *gin.Engine, proberService *prober.ProberService) {
+func setupRoutes(r *gin.Engine, proberService *prober.ProberService, historyService *history.Service, dispatcher *notify.Dispatcher) {
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler()
 	adminHandler := handlers.NewAdminHandler()
-	serverHandler := handlers.NewServerHandler(proberService)
+	serverHandler := handlers.NewServerHandler(proberService, historyService)
+	historyHandler := handlers.NewHistoryHandler(historyService)
+	notificationHandler := handlers.NewNotificationHandler(dispatcher)
+	alertHandler := handlers.NewAlertHandler()
+	maintenanceHandler := handlers.NewMaintenanceHandler()
+	rconHandler := handlers.NewRCONHandler()
+	proberHandler := handlers.NewProberHandler(proberService)
 
 	// Initialize JWT service
 	jwtService := auth.NewJWTService()
@@ -84,8 +116,12 @@ func setupRoutes(r *gin.Engine, proberService *prober.ProberService) {
 		api.Use(middleware.RateLimitMiddleware(20, 10*time.Second))
 
 		// Public endpoints - Server status endpoints
+		api.GET("/server-types", serverHandler.GetServerTypes)
 		api.GET("/servers", serverHandler.GetServers)
 		api.GET("/servers/:id", serverHandler.GetServerByID)
+		api.GET("/servers/:id/icon", serverHandler.GetServerIcon)
+		api.GET("/servers/:id/history", historyHandler.GetServerHistory)
+		api.GET("/servers/:id/uptime", historyHandler.GetServerUptime)
 
 		// Auth endpoints
 		auth := api.Group("/auth")
@@ -111,12 +147,45 @@ func setupRoutes(r *gin.Engine, proberService *prober.ProberService) {
 			admin.POST("/servers", serverHandler.CreateServer)
 			admin.PUT("/servers/:id", serverHandler.UpdateServer)
 			admin.DELETE("/servers/:id", serverHandler.DeleteServer)
+			admin.POST("/servers/:id/rcon", rconHandler.Execute)
+			admin.GET("/servers/:id/rcon/audit", rconHandler.GetAuditLog)
 
 			// User management
 			admin.POST("/users", adminHandler.CreateUser)
 			admin.GET("/users", adminHandler.GetUsers)
 			admin.DELETE("/users/:id", adminHandler.DeleteUser)
 			admin.POST("/users/:id/reset-password", adminHandler.ResetUserPassword)
+
+			// Notification channels
+			admin.GET("/notifications", notificationHandler.GetChannels)
+			admin.POST("/notifications", notificationHandler.CreateChannel)
+			admin.PUT("/notifications/:id", notificationHandler.UpdateChannel)
+			admin.DELETE("/notifications/:id", notificationHandler.DeleteChannel)
+			admin.GET("/notifications/:id/deliveries", notificationHandler.GetDeliveries)
+			admin.POST("/notifications/:id/test", notificationHandler.TestChannel)
+
+			// Alert rules
+			admin.GET("/alerts", alertHandler.GetRules)
+			admin.POST("/alerts", alertHandler.CreateRule)
+			admin.GET("/alerts/states", alertHandler.GetStates)
+			admin.PUT("/alerts/:id", alertHandler.UpdateRule)
+			admin.DELETE("/alerts/:id", alertHandler.DeleteRule)
+
+			// Maintenance windows
+			admin.GET("/maintenance", maintenanceHandler.GetWindows)
+			admin.POST("/maintenance", maintenanceHandler.CreateWindow)
+			admin.PUT("/maintenance/:id", maintenanceHandler.UpdateWindow)
+			admin.DELETE("/maintenance/:id", maintenanceHandler.DeleteWindow)
+
+			// Prober control
+			admin.GET("/prober", proberHandler.GetStats)
+			admin.POST("/prober/probe", proberHandler.ProbeAll)
+			admin.POST("/prober/probe/:id", proberHandler.ProbeServer)
+			admin.POST("/prober/pause", proberHandler.Pause)
+			admin.POST("/prober/resume", proberHandler.Resume)
+			admin.PUT("/prober/interval", proberHandler.SetInterval)
+			admin.GET("/prober/cache", proberHandler.GetCacheStats)
+			admin.DELETE("/prober/cache", proberHandler.ClearCache)
 		}
 	}
 